@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCostCache(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "cost-000000000000-2024-01.json")
+
+	// No cache file, or no cache at all, is a cache miss
+	var cache costCache
+	if read_cost_cache(cacheFile, &cache) || read_cost_cache("", &cache) {
+		t.Fatal("expected a missing cache to be a miss")
+	}
+
+	if err := write_cost_cache(cacheFile, []byte(`{"costs":{"Amazon EC2":1.5}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !read_cost_cache(cacheFile, &cache) || cache.Costs["Amazon EC2"] != 1.5 {
+		t.Errorf("expected the written costs to be read back, got %v", cache.Costs)
+	}
+
+	// The cache is private and the temp file was renamed away
+	info, err := os.Stat(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600, got %#o", info.Mode().Perm())
+	}
+	entries, err := ioutil.ReadDir(filepath.Dir(cacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the cache file, got %d files", len(entries))
+	}
+}
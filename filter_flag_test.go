@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+)
+
+func TestFilterFlag(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		filter Filter
+		fails  bool
+	}{
+		{name: "single value", value: "Name=vpc-id,Values=vpc-12345", filter: Filter{Name: "vpc-id", Values: []string{"vpc-12345"}}},
+		// Every value after Values= is split like the AWS CLI does
+		{name: "multiple values", value: "Name=instance-state-name,Values=running,stopped", filter: Filter{Name: "instance-state-name", Values: []string{"running", "stopped"}}},
+		{name: "tag filter", value: "Name=tag:Environment,Values=prod", filter: Filter{Name: "tag:Environment", Values: []string{"prod"}}},
+		{name: "missing values", value: "Name=vpc-id", fails: true},
+		{name: "empty values", value: "Name=vpc-id,Values=", fails: true},
+		{name: "missing name", value: "Values=vpc-12345", fails: true},
+		{name: "wrong order", value: "Values=vpc-12345,Name=vpc-id", fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var filters filterFlag
+			err := filters.Set(tc.value)
+			if (err != nil) != tc.fails {
+				t.Fatalf("Set(%q) returned %v, expected failure %v", tc.value, err, tc.fails)
+			}
+			if tc.fails {
+				return
+			}
+			if len(filters) != 1 || filters[0].Name != tc.filter.Name || strings.Join(filters[0].Values, ",") != strings.Join(tc.filter.Values, ",") {
+				t.Errorf("Set(%q) = %v, expected %v", tc.value, filters, tc.filter)
+			}
+		})
+	}
+}
+
+func TestTagFilterFlag(t *testing.T) {
+	var filters filterFlag
+	tagFilters := tagFilterFlag{&filters}
+
+	// The tag key becomes a tag: filter name, the value may hold an equals sign
+	for _, value := range []string{"Environment=prod", "Query=a=b"} {
+		if err := tagFilters.Set(value); err != nil {
+			t.Fatalf("Set(%q) returned %v", value, err)
+		}
+	}
+	if filters.String() != "Name=tag:Environment,Values=prod Name=tag:Query,Values=a=b" {
+		t.Errorf("unexpected filters %s", filters.String())
+	}
+
+	for _, value := range []string{"Environment", "=prod"} {
+		if err := tagFilters.Set(value); err == nil {
+			t.Errorf("expected Set(%q) to fail", value)
+		}
+	}
+}
+
+func TestElbFilterMatch(t *testing.T) {
+	defer func() { elbFilters = make(filterFlag, 0) }()
+	lb := &elb.LoadBalancerDescription{
+		LoadBalancerName: aws.String("web"),
+		VPCId:            aws.String("vpc-12345"),
+	}
+
+	// Without filters every load balancer matches
+	if !elb_filter_match(lb) {
+		t.Error("expected a match without filters")
+	}
+
+	// One value of every filter must match
+	elbFilters = filterFlag{{Name: "vpc-id", Values: []string{"vpc-0000", "vpc-12345"}}}
+	if !elb_filter_match(lb) {
+		t.Error("expected a match on vpc-id")
+	}
+	elbFilters = append(elbFilters, Filter{Name: "load-balancer-name", Values: []string{"api"}})
+	if elb_filter_match(lb) {
+		t.Error("expected no match when the load-balancer-name filter does not match")
+	}
+}
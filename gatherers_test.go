@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Register aws_ec2_tags for one instance with the given tags on the registry
+func register_instance(reg prometheus.Registerer, id string, tags map[string]string) {
+	instance := map[string]map[string]string{id: tags}
+	new_collector_result(reg, "aws_ec2_tags", "test", "InstanceId", instance).Set(id, 1)
+}
+
+// Return the account_id label of every aws_ec2_tags series
+func gathered_accounts(t *testing.T, gatherer prometheus.Gatherer) map[string]bool {
+	t.Helper()
+	mfs, err := gatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := make(map[string]bool)
+	for _, mf := range mfs {
+		if mf.GetName() != "aws_ec2_tags" {
+			continue
+		}
+		for _, m := range mf.Metric {
+			accounts[label_value(m, accountIdLabel)] = true
+		}
+	}
+	return accounts
+}
+
+func label_value(m *dto.Metric, name string) string {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func TestAccountGatherers_DifferentTagKeys(t *testing.T) {
+	// Each account registers aws_ec2_tags with the tag keys of its own instances
+	first := prometheus.NewRegistry()
+	register_instance(first, "i-1111", map[string]string{"Name": "web"})
+	second := prometheus.NewRegistry()
+	register_instance(second, "i-2222", map[string]string{"Team": "data", "Owner": "ops"})
+
+	gatherers := prometheus.Gatherers{
+		accountGatherer{"111111111111", first},
+		accountGatherer{"222222222222", second},
+	}
+	accounts := gathered_accounts(t, gatherers)
+	if !accounts["111111111111"] || !accounts["222222222222"] {
+		t.Errorf("expected aws_ec2_tags of both accounts, got %v", accounts)
+	}
+}
+
+func TestServiceGatherers_DifferentTagKeys(t *testing.T) {
+	defer func() { serviceGatherers = make(map[string]prometheus.Gatherers) }()
+
+	// With --split-by-service each account registers the service on its own registry
+	register_instance(new_service_registry("ec2", "111111111111"), "i-1111", map[string]string{"Name": "web"})
+	register_instance(new_service_registry("ec2", "222222222222"), "i-2222", map[string]string{"Team": "data", "Owner": "ops"})
+
+	accounts := gathered_accounts(t, serviceGatherers["ec2"])
+	if !accounts["111111111111"] || !accounts["222222222222"] {
+		t.Errorf("expected aws_ec2_tags of both accounts in the ec2 file, got %v", accounts)
+	}
+}
@@ -0,0 +1,242 @@
+//go:build integration
+// +build integration
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Run with:
+//   go test -tags integration -run TestIntegration
+// A LocalStack container is started with docker unless LOCALSTACK_ENDPOINT
+// points at one that is already running.
+
+const integrationRegion = "us-east-1"
+
+// Return the endpoint of a running LocalStack, starting a container if needed
+func localstack_endpoint(t *testing.T) string {
+	t.Helper()
+	if endpoint := os.Getenv("LOCALSTACK_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("LOCALSTACK_ENDPOINT is not set and docker is not available")
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm", "-p", "4566:4566", "localstack/localstack").Output()
+	if err != nil {
+		t.Fatalf("unable to start LocalStack: %v", err)
+	}
+	container := strings.TrimSpace(string(out))
+	t.Cleanup(func() {
+		exec.Command("docker", "stop", container).Run()
+	})
+
+	// Wait for LocalStack to report healthy
+	endpoint := "http://localhost:4566"
+	for i := 0; i < 60; i++ {
+		resp, err := http.Get(endpoint + "/_localstack/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return endpoint
+			}
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatal("LocalStack did not become healthy in time")
+	return ""
+}
+
+// Build a zip archive holding a minimal Lambda handler
+func lambda_zip(t *testing.T) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	f, err := w.Create("index.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("def handler(event, context):\n    return event\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Create one tagged resource per collector in LocalStack
+func create_integration_resources(t *testing.T, sess *session.Session) {
+	t.Helper()
+
+	// EC2 instance
+	ec2Svc := ec2.New(sess)
+	reservation, err := ec2Svc.RunInstances(&ec2.RunInstancesInput{
+		ImageId:      aws.String("ami-12345678"),
+		InstanceType: aws.String("t2.micro"),
+		MinCount:     aws.Int64(1),
+		MaxCount:     aws.Int64(1),
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeInstance),
+				Tags: []*ec2.Tag{
+					{Key: aws.String("Name"), Value: aws.String("integration-instance")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	instanceId := reservation.Instances[0].InstanceId
+
+	// ASG
+	asgSvc := autoscaling.New(sess)
+	if _, err := asgSvc.CreateLaunchConfiguration(&autoscaling.CreateLaunchConfigurationInput{
+		LaunchConfigurationName: aws.String("integration-lc"),
+		ImageId:                 aws.String("ami-12345678"),
+		InstanceType:            aws.String("t2.micro"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := asgSvc.CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName:    aws.String("integration-asg"),
+		LaunchConfigurationName: aws.String("integration-lc"),
+		MinSize:                 aws.Int64(1),
+		MaxSize:                 aws.Int64(1),
+		AvailabilityZones:       []*string{aws.String(integrationRegion + "a")},
+		Tags: []*autoscaling.Tag{
+			{Key: aws.String("Team"), Value: aws.String("integration"), PropagateAtLaunch: aws.Bool(true)},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// EFS filesystem
+	if _, err := efs.New(sess).CreateFileSystem(&efs.CreateFileSystemInput{
+		CreationToken: aws.String("integration-efs"),
+		Tags: []*efs.Tag{
+			{Key: aws.String("Name"), Value: aws.String("integration-efs")},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// ELB with the EC2 instance registered
+	elbSvc := elb.New(sess)
+	if _, err := elbSvc.CreateLoadBalancer(&elb.CreateLoadBalancerInput{
+		LoadBalancerName:  aws.String("integration-elb"),
+		AvailabilityZones: []*string{aws.String(integrationRegion + "a")},
+		Listeners: []*elb.Listener{
+			{
+				InstancePort:     aws.Int64(80),
+				LoadBalancerPort: aws.Int64(80),
+				Protocol:         aws.String("HTTP"),
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := elbSvc.RegisterInstancesWithLoadBalancer(&elb.RegisterInstancesWithLoadBalancerInput{
+		LoadBalancerName: aws.String("integration-elb"),
+		Instances:        []*elb.Instance{{InstanceId: instanceId}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lambda function
+	if _, err := lambda.New(sess).CreateFunction(&lambda.CreateFunctionInput{
+		FunctionName: aws.String("integration-function"),
+		Description:  aws.String("integration test function"),
+		Runtime:      aws.String("python3.9"),
+		Handler:      aws.String("index.handler"),
+		Role:         aws.String("arn:aws:iam::000000000000:role/integration"),
+		Code:         &lambda.FunctionCode{ZipFile: lambda_zip(t)},
+		Tags:         map[string]*string{"Team": aws.String("integration")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// RDS instance
+	if _, err := rds.New(sess).CreateDBInstance(&rds.CreateDBInstanceInput{
+		DBInstanceIdentifier: aws.String("integration-db"),
+		DBName:               aws.String("integration"),
+		DBInstanceClass:      aws.String("db.t3.micro"),
+		Engine:               aws.String("postgres"),
+		AllocatedStorage:     aws.Int64(20),
+		MasterUsername:       aws.String("integration"),
+		MasterUserPassword:   aws.String("integration"),
+		Tags: []*rds.Tag{
+			{Key: aws.String("Team"), Value: aws.String("integration")},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntegration(t *testing.T) {
+	endpoint := localstack_endpoint(t)
+
+	// Point every collector at LocalStack with dummy credentials
+	os.Setenv("AWS_ACCESS_KEY_ID", "test")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	endpointUrl = endpoint
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Endpoint:    aws.String(endpoint),
+		Region:      aws.String(integrationRegion),
+		Credentials: credentials.NewStaticCredentials("test", "test", ""),
+	}))
+	create_integration_resources(t, sess)
+
+	// The collector metrics are gathered from the registry of the account
+	gather_data(integrationRegion)
+	gatherers := append(prometheus.Gatherers{registry}, accountGatherers...)
+	metricsString := prometheus_gather(gatherers, "text")
+
+	expected := []string{
+		"aws_asg_instances{",
+		`AutoScalingGroupName="integration-asg"`,
+		"aws_ec2_tags{",
+		`Name="integration-instance"`,
+		"aws_efs_tags{",
+		`Name="integration-efs"`,
+		"aws_elb_instances{",
+		`LoadBalancerName="integration-elb"`,
+		"aws_lambda_tags{",
+		`FunctionName="integration-function"`,
+		"aws_rds_tags{",
+		`DBInstanceIdentifier="integration-db"`,
+		`Team="integration"`,
+		// LocalStack reports the default test account
+		`account_id="000000000000"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(metricsString, e) {
+			t.Errorf("expected metrics output to contain %s", e)
+		}
+	}
+	if t.Failed() {
+		t.Log(metricsString)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/macie2"
+)
+
+func TestMacieNotEnabled(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{awserr.New(macie2.ErrCodeAccessDeniedException, "Macie is not enabled.", nil), true},
+		{awserr.New(macie2.ErrCodeAccessDeniedException, "User is not authorized to perform: macie2:GetMacieSession", nil), false},
+		{awserr.New(macie2.ErrCodeThrottlingException, "Rate exceeded", nil), false},
+		{errors.New("Macie is not enabled"), false},
+	}
+	for _, c := range cases {
+		if got := macie_not_enabled(c.err); got != c.want {
+			t.Errorf("macie_not_enabled(%q) = %v, expected %v", c.err, got, c.want)
+		}
+	}
+}
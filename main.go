@@ -9,6 +9,40 @@ Options:
     default: /var/lib/node_exporter/metrics/custom_metrics.prom
 --region us-east-1
     default: us-west-2
+--profile name
+    shared config profile, ignored when AWS_PROFILE is set
+--metric-help-file /some/help.yaml
+    metric name to help text overrides
+--required-tags Owner,Environment,Team
+    count the required tags missing from every resource
+--discover-accounts
+    gather every active account of the AWS Organization
+--assume-role-arn arn:aws:iam::{accountId}:role/RoleName
+    role assumed in each discovered account
+--ec2-filter Name=vpc-id,Values=vpc-12345
+    only describe the matching EC2 instances, repeatable
+--asg-filter Name=tag:Environment,Values=prod
+    only describe the matching Auto Scaling groups, repeatable
+--asg-tag-filter Environment=prod
+    only describe the Auto Scaling groups with the tag, repeatable
+--elb-filter Name=vpc-id,Values=vpc-12345
+    only keep the matching ELBs by load-balancer-name or vpc-id, repeatable
+--tag-rename aws:cloudformation:stack-name=cfn_stack
+    use the label name for the tag key instead of sanitizing it, repeatable
+--label-value-max-length 256
+    default: 0, unlimited
+    truncate longer tag label values and suffix them with ...
+--output-format text|protobuf
+    default: text
+    protobuf writes the delimited binary format, a .prom out-file becomes .pb
+--split-by-service
+    write each service to aws_<service>.prom in the out-file directory
+--remote-write-url https://host/api/v1/push
+    also push the metrics to a Prometheus remote write endpoint
+--remote-write-bearer-token token
+    bearer token sent to the remote write endpoint
+--remote-write-tls-cert /some/client.pem
+    PEM file holding the client certificate and key for the remote write endpoint
 --help
 
 Build:
@@ -26,8 +60,11 @@ package main
 
 import (
 	"bytes"
+	"crypto/tls"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/http"
@@ -36,311 +73,568 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/amplify"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apprunner"
+	"github.com/aws/aws-sdk-go/service/appsync"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/backup"
+	"github.com/aws/aws-sdk-go/service/batch"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/codestarconnections"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/comprehend"
+	"github.com/aws/aws-sdk-go/service/connect"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/datasync"
+	"github.com/aws/aws-sdk-go/service/directconnect"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/frauddetector"
+	"github.com/aws/aws-sdk-go/service/globalaccelerator"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/aws/aws-sdk-go/service/healthlake"
+	"github.com/aws/aws-sdk-go/service/inspector2"
+	"github.com/aws/aws-sdk-go/service/iot"
+	"github.com/aws/aws-sdk-go/service/kafka"
+	"github.com/aws/aws-sdk-go/service/lakeformation"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go/service/macie2"
+	"github.com/aws/aws-sdk-go/service/mediaconvert"
+	"github.com/aws/aws-sdk-go/service/networkfirewall"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/outposts"
+	"github.com/aws/aws-sdk-go/service/pinpoint"
+	"github.com/aws/aws-sdk-go/service/ram"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rekognition"
+	"github.com/aws/aws-sdk-go/service/sagemaker"
+	"github.com/aws/aws-sdk-go/service/savingsplans"
+	"github.com/aws/aws-sdk-go/service/securityhub"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/shield"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite"
+	"github.com/aws/aws-sdk-go/service/transfer"
+	"github.com/aws/aws-sdk-go/service/wafv2"
+	"github.com/aws/aws-sdk-go/service/workspaces"
 
+	"github.com/golang/snappy"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/prompb"
+	"gopkg.in/yaml.v2"
 )
 
 func main() {
 	// Set up options
 	outFile := flag.String("out-file", "/var/lib/node_exporter/metrics/custom_metrics.prom", "Path to output file for prometheus exposition metrics")
 	region := flag.String("region", "us-west-2", "Region to gather metrics for")
+	outputFormat := flag.String("output-format", "text", "Format of the output file, text or protobuf")
+	discoverAccounts := flag.Bool("discover-accounts", false, "Gather metrics for every active account in the AWS Organization")
+	assumeRoleArn := flag.String("assume-role-arn", "", "Role to assume in each discovered account, {accountId} is replaced with the account id")
+	flag.StringVar(&profile, "profile", "", "Shared config profile to use, ignored when AWS_PROFILE is set")
+	metricHelpFile := flag.String("metric-help-file", "", "YAML file mapping metric names to their help text")
+	requiredTagsFlag := flag.String("required-tags", "", "Comma separated tags every resource must have, e.g. Owner,Environment,Team")
+	flag.Var(tagRenames, "tag-rename", "Map a tag key to a label name like aws:cloudformation:stack-name=cfn_stack, repeatable")
+	flag.IntVar(&labelValueMaxLength, "label-value-max-length", 0, "Truncate tag label values longer than this many characters, 0 is unlimited")
+	flag.BoolVar(&verbose, "verbose", false, "Log every truncated label value in full")
+	flag.BoolVar(&splitByService, "split-by-service", false, "Write the metrics of each service to its own file in the out-file directory")
+	remoteWriteUrl := flag.String("remote-write-url", "", "Prometheus remote write endpoint to push the metrics to")
+	remoteWriteBearerToken := flag.String("remote-write-bearer-token", "", "Bearer token for the remote write endpoint")
+	remoteWriteTlsCert := flag.String("remote-write-tls-cert", "", "PEM file with the client certificate and key for the remote write endpoint")
+	flag.Var(&ec2Filters, "ec2-filter", "EC2 DescribeInstances filter like Name=vpc-id,Values=vpc-12345, repeatable")
+	flag.Var(&asgFilters, "asg-filter", "Auto Scaling DescribeAutoScalingGroups filter like Name=tag:Environment,Values=prod, repeatable")
+	flag.Var(tagFilterFlag{&asgFilters}, "asg-tag-filter", "Only collect Auto Scaling groups tagged key=value, repeatable")
+	flag.Var(&elbFilters, "elb-filter", "ELB filter on load-balancer-name or vpc-id like Name=vpc-id,Values=vpc-12345, repeatable")
 	flag.Parse()
 
-	gather_data(*region)
-	metricsString := prometheus_gather()
-	write_file(*outFile, metricsString)
-}
-
-func gather_data(region string) {
-	get_asg_membership(region)
-	get_ec2_instance_tags(region)
-	get_efs_tags(region)
-	get_elb_membership(region)
-	get_lambda_tags(region)
-	get_rds_tags(region)
-}
+	for _, t := range strings.Split(*requiredTagsFlag, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			requiredTags = append(requiredTags, t)
+		}
+	}
 
-// Create the prometheus regestry
-var (
-	registry = prometheus.NewRegistry()
-)
+	if *outputFormat != "text" && *outputFormat != "protobuf" {
+		log.Fatalf("Unknown output format '%s', must be text or protobuf", *outputFormat)
+	}
+	if *metricHelpFile != "" {
+		load_metric_help(*metricHelpFile)
+	}
+	for _, f := range elbFilters {
+		if f.Name != "load-balancer-name" && f.Name != "vpc-id" {
+			log.Fatalf("Unknown --elb-filter name '%s', must be load-balancer-name or vpc-id", f.Name)
+		}
+	}
+	if *discoverAccounts && !strings.Contains(*assumeRoleArn, "{accountId}") {
+		log.Fatal("--discover-accounts needs an --assume-role-arn like 'arn:aws:iam::{accountId}:role/RoleName'")
+	}
 
-// Gather all prometheus metrics from the registry
-func prometheus_gather() string {
+	if *discoverAccounts {
+		gather_organization_data(*region, *assumeRoleArn)
+	} else {
+		gather_data(*region)
+	}
+	set_last_collected()
 	gatherers := prometheus.Gatherers{
 		registry,
 	}
-	gathering, err := gatherers.Gather()
-	if err != nil {
-		fmt.Println(err)
+	gatherers = append(gatherers, accountGatherers...)
+	metricsString := prometheus_gather(gatherers, *outputFormat)
+	write_file(*outFile, metricsString, *outputFormat)
+
+	// Every service gets its own file next to the out-file, the out-file keeps the API call and collection metrics
+	for service, serviceGatherer := range serviceGatherers {
+		serviceFile := filepath.Join(filepath.Dir(*outFile), "aws_"+service+".prom")
+		write_file(serviceFile, prometheus_gather(serviceGatherer, *outputFormat), *outputFormat)
+		gatherers = append(gatherers, serviceGatherer)
 	}
 
-	// Create the output buffer and write out all of the gathered metrics
-	out := &bytes.Buffer{}
-	for _, mf := range gathering {
-		if _, err := expfmt.MetricFamilyToText(out, mf); err != nil {
-			panic(err)
+	if *remoteWriteUrl != "" {
+		if err := remote_write(gatherers, *remoteWriteUrl, *remoteWriteBearerToken, *remoteWriteTlsCert); err != nil {
+			log.Fatal(err)
 		}
 	}
-	//    fmt.Print(out.String())
-	metricsString := out.String()
-	return metricsString
 }
 
-// Ensure all Prometheus labels are valid
-func sanatize_tag(tag string) string {
-	// Metric labels allow only "^[a-zA-Z_][a-zA-Z0-9_]*$"
-	// Need to fix tags like: 'aws:autoscaling:groupName'
-	regex_full := regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
-	regex_first_number := regexp.MustCompile("^[0-9_]*")
-	regex_valid_segment := regexp.MustCompile("[a-zA-Z0-9_]*")
+// A Collector gathers the metrics of one AWS service into a registry
+type Collector interface {
+	Collect(sess *session.Session, region string, reg prometheus.Registerer) error
+}
 
-	// Check tag against regex and return if it passes
-	if regex_full.MatchString(tag) {
-		return tag
+// Adapter to use a plain get_* function as a Collector
+type collectorFunc func(sess *session.Session, region string, reg prometheus.Registerer) error
+
+func (f collectorFunc) Collect(sess *session.Session, region string, reg prometheus.Registerer) error {
+	return f(sess, region, reg)
+}
+
+// Adapter for the collectors which share one RDS client
+type rdsCollectorFunc struct {
+	svc     *rds.RDS
+	collect func(svc *rds.RDS, reg prometheus.Registerer) error
+}
+
+func (c rdsCollectorFunc) Collect(sess *session.Session, region string, reg prometheus.Registerer) error {
+	return c.collect(c.svc, reg)
+}
+
+// The account collected from, looked up once so collectors don't each ask STS
+type awsAccount struct {
+	id        string
+	partition string
+}
+
+// Adapter for the collectors which need the account, to build ARNs or cache per account
+type accountCollectorFunc struct {
+	account awsAccount
+	collect func(sess *session.Session, region string, account awsAccount, reg prometheus.Registerer) error
+}
+
+func (c accountCollectorFunc) Collect(sess *session.Session, region string, reg prometheus.Registerer) error {
+	if c.account.id == "" {
+		return fmt.Errorf("the account id for %s could not be looked up, skipping", region)
 	}
+	return c.collect(sess, region, c.account, reg)
+}
 
-	// Slice the string keeping only valid portions
-	valid_slice := regex_valid_segment.FindAllStringSubmatch(tag, -1)
-	restitch := make([]string, 0, len(valid_slice)+1)
-	for i, v := range valid_slice {
-		restitch = append(restitch, strings.Join(v[:], ""))
-		if i < len(valid_slice)-1 {
-			restitch = append(restitch, "_")
+// Label added to every metric gathered from an account, tag labels are never given this name
+const accountIdLabel = "account_id"
+
+// Adds the account_id label to every metric gathered from the registry of one account
+type accountGatherer struct {
+	accountId string
+	gatherer  prometheus.Gatherer
+}
+
+func (a accountGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := a.gatherer.Gather()
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			m.Label = append(m.Label, &dto.LabelPair{
+				Name:  aws.String(accountIdLabel),
+				Value: aws.String(a.accountId),
+			})
+			sort.Slice(m.Label, func(i, j int) bool {
+				return m.Label[i].GetName() < m.Label[j].GetName()
+			})
 		}
 	}
-	// Restitch string togeather with underscore
-	// Validate and return
-	valid_string := strings.Join(restitch[:], "")
-	if regex_full.MatchString(valid_string) {
-		return valid_string
-	}
+	return mfs, err
+}
 
-	// Test for the first character matching [0-9] and pad with underscore
-	// Validate and retrune else panic
-	pad_slice := make([]string, 0, len(valid_string)+1)
-	if regex_first_number.MatchString(valid_string) {
-		pad_slice = append(pad_slice, "_", valid_string)
+// A filter given on the command line as Name=<name>,Values=<value>[,<value>...], like the AWS CLI --filters
+type Filter struct {
+	Name   string
+	Values []string
+}
+
+// Repeatable filter flag, every value is validated when the flags are parsed
+type filterFlag []Filter
+
+func (f *filterFlag) String() string {
+	filters := make([]string, 0, len(*f))
+	for _, v := range *f {
+		filters = append(filters, "Name="+v.Name+",Values="+strings.Join(v.Values, ","))
 	}
-	pad_string := strings.Join(pad_slice[:], "")
-	if regex_full.MatchString(pad_string) {
-		return pad_string
-	} else {
-		errorString := ("Can not fix tag '" + tag + "' to validate against regex '^[a-zA-Z][a-zA-Z0-9_]*$'")
-		panic(errorString)
+	return strings.Join(filters, " ")
+}
+
+func (f *filterFlag) Set(value string) error {
+	match := filterRegex.FindStringSubmatch(value)
+	if match == nil {
+		return fmt.Errorf("filter '%s' must look like Name=<name>,Values=<value>", value)
 	}
+	*f = append(*f, Filter{Name: match[1], Values: strings.Split(match[2], ",")})
+	return nil
 }
 
-func write_file(outFile string, fileContents string) {
-	dir, file := filepath.Split(outFile)
-	s1 := rand.NewSource(time.Now().UnixNano())
-	r1 := rand.New(s1)
-	tmpName := filepath.Join(dir, fmt.Sprintf("%s.tmp%d", file, r1.Intn(10000)))
+// Repeatable original_key=new_label_name flag mapping tag keys to label names
+type tagRenameFlag map[string]string
 
-	tmpFile, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
-	if err != nil {
-		log.Fatal(err)
+func (f tagRenameFlag) String() string {
+	renames := make([]string, 0, len(f))
+	for k, v := range f {
+		renames = append(renames, k+"="+v)
 	}
+	sort.Strings(renames)
+	return strings.Join(renames, ",")
+}
 
-	defer os.Remove(tmpName)
-
-	if _, err := tmpFile.Write([]byte(fileContents)); err != nil {
-		log.Fatal(err)
+// Tag keys may hold an equals sign while label names cannot, so the value is split on the last one
+func (f tagRenameFlag) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("tag rename '%s' must look like original_key=new_label_name", value)
 	}
-	if err := tmpFile.Close(); err != nil {
-		log.Fatal(err)
+	key, label := value[:i], value[i+1:]
+	if !regex_full.MatchString(label) {
+		return fmt.Errorf("tag rename '%s' must rename to a valid label name matching '^[a-zA-Z_][a-zA-Z0-9_]*$'", value)
 	}
-
-	if err := os.Rename(tmpName, outFile); err != nil {
-		log.Fatal(err)
+	if label == accountIdLabel {
+		return fmt.Errorf("tag rename '%s' clashes with the %s label of every metric", value, accountIdLabel)
 	}
+	for k, v := range f {
+		if v == label && k != key {
+			return fmt.Errorf("tag rename '%s' clashes with the rename of '%s'", value, k)
+		}
+	}
+	f[key] = label
+	return nil
 }
 
-// Lists all instances in an ASG in us-west-2
-func get_asg_membership(region string) {
-	// Set up for a proxy, if one exists
-	httpclient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(*http.Request) (*url.URL, error) {
-				val, ok := os.LookupEnv("HTTPS_PROXY")
-				if !ok {
-					//fmt.Println("HTTPS_PROXY not set")
-					return nil, nil
-				} else {
-					//fmt.Printf("HTTPS_PROXY=%s\n", val)
-					return url.Parse(val)
-				}
-			},
-		},
+// Repeatable key=value tag filter flag, added to the filters as Name=tag:<key>,Values=<value>
+type tagFilterFlag struct {
+	filters *filterFlag
+}
+
+func (f tagFilterFlag) String() string {
+	return ""
+}
+
+func (f tagFilterFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("tag filter '%s' must look like key=value", value)
 	}
+	*f.filters = append(*f.filters, Filter{Name: "tag:" + parts[0], Values: []string{parts[1]}})
+	return nil
+}
 
-	// Initialize a session
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// The gauge of a collector along with the labels of each resource it covers
+type CollectorResult struct {
+	Gauge  *prometheus.GaugeVec
+	Labels map[string]map[string]string
 
-	// Create AutoScaling service client
-	svc := autoscaling.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
+	// Label holding the resource id, and the label names in sort order
+	idLabel string
+	keys    []string
+}
+
+func gather_data(region string) {
+	registry.MustRegister(apiCalls, apiCallDuration, resourceCount)
+	sess := new_session()
 
-	result, err := svc.DescribeAutoScalingGroups(nil)
+	// Look up the account once, its registry adds the account_id label to every metric like a
+	// discovered account, so metrics merged from several accounts stay unambiguous
+	identity, err := sts.New(sess, &aws.Config{Region: aws.String(region)}).GetCallerIdentity(nil)
+	account := awsAccount{partition: partition_for_region(region)}
+	var accountRegistry prometheus.Registerer = registry
 	if err != nil {
 		fmt.Println(err.Error())
-		return
+	} else {
+		account = awsAccount{aws.StringValue(identity.Account), partition_for_arn(aws.StringValue(identity.Arn), region)}
+		reg := prometheus.NewRegistry()
+		accountGatherers = append(accountGatherers, accountGatherer{account.id, reg})
+		accountRegistry = reg
 	}
 
-	// Create and register a new gauge for prometheus
-	asg := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "aws_asg_instances",
-			Help: "Metric per EC2 instances within an ASG.",
-		},
-		[]string{"AutoScalingGroupName", "AutoScalingGroupARN", "InstanceId"},
-	)
-	registry.MustRegister(asg)
-
-	// Iterate through all groups, gather instances adding a metric for each
-	for _, f := range result.AutoScalingGroups {
-		for _, v := range f.Instances {
-			asg.WithLabelValues(aws.StringValue(f.AutoScalingGroupName), aws.StringValue(f.AutoScalingGroupARN), *v.InstanceId).Set(1)
+	registryFor := func(service string) prometheus.Registerer {
+		return accountRegistry
+	}
+	if splitByService {
+		registryFor = func(service string) prometheus.Registerer {
+			return new_service_registry(service, account.id)
 		}
 	}
+	collect_all(sess, region, account, registryFor)
 }
 
-// Lists all tags for all instances in us-west-2
-// Iterate through instances to ONLY look up keys and add unique to map
-// Create new guage with keys from map
-// Iterate through instances making one guage metric each with all key:value pairs populated
-func get_ec2_instance_tags(region string) {
-	// Set up for a proxy, if one exists
-	httpclient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(*http.Request) (*url.URL, error) {
-				val, ok := os.LookupEnv("HTTPS_PROXY")
-				if !ok {
-					return nil, nil
-				} else {
-					return url.Parse(val)
-				}
-			},
-		},
-	}
-
-	// Initialize a session
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// Gather every active account of the AWS Organization in parallel, each through the assumed role
+func gather_organization_data(region string, assumeRoleArn string) {
+	registry.MustRegister(apiCalls, apiCallDuration, resourceCount)
+	sess := new_session()
 
-	// Create EC2 service client
-	svc := ec2.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
+	// Organizations is a global service, the endpoint lives in us-east-1
+	svc := organizations.New(sess, &aws.Config{Region: aws.String("us-east-1")})
 
-	result, err := svc.DescribeInstances(nil)
+	// Page through all of the accounts
+	accounts := make([]*organizations.Account, 0)
+	err := svc.ListAccountsPages(&organizations.ListAccountsInput{},
+		func(page *organizations.ListAccountsOutput, lastPage bool) bool {
+			accounts = append(accounts, page.Accounts...)
+			return true
+		})
 	if err != nil {
 		fmt.Println(err.Error())
 		return
 	}
 
-	// Iterate through all the instances, gather the tag names and add them to the tags map
-	tags := make(map[string]string)
-	for _, f := range result.Reservations {
-		if len(f.Instances) > 0 {
-			for _, v := range f.Instances {
-				if len(v.Tags) > 0 {
-					for _, v := range v.Tags {
-						// If the key is not in the map, add it
-						if _, ok := tags[*v.Key]; !ok {
-							tags[*v.Key] = ""
-						}
-					}
-				}
+	// Each account collects into its own registry, so the label sets of different accounts never clash
+	var wg sync.WaitGroup
+	for _, f := range accounts {
+		if aws.StringValue(f.Status) != organizations.AccountStatusActive {
+			continue
+		}
+		accountId := aws.StringValue(f.Id)
+		account := awsAccount{accountId, partition_for_arn(aws.StringValue(f.Arn), region)}
+		roleArn := strings.Replace(assumeRoleArn, "{accountId}", accountId, -1)
+		accountSess := sess.Copy(&aws.Config{
+			Credentials: stscreds.NewCredentials(sess, roleArn),
+		})
+		accountRegistry := prometheus.NewRegistry()
+		accountGatherers = append(accountGatherers, accountGatherer{accountId, accountRegistry})
+		registryFor := func(service string) prometheus.Registerer {
+			return accountRegistry
+		}
+		if splitByService {
+			registryFor = func(service string) prometheus.Registerer {
+				return new_service_registry(service, accountId)
 			}
 		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collect_all(accountSess, region, account, registryFor)
+		}()
 	}
+	wg.Wait()
+}
 
-	// Gather all tags for each instance and pupulate instance map
-	instances := make(map[string]map[string]string)
-	// Iterate through all the instances and create one entry with all tags
-	for _, f := range result.Reservations {
-		if len(f.Instances) > 0 {
-			for _, i := range f.Instances {
-				// Initialize the map for this instance
-				instances[*i.InstanceId] = make(map[string]string)
+// Run every collector with one session, registering the metrics on the registry returned for its service
+func collect_all(sess *session.Session, region string, account awsAccount, registryFor func(service string) prometheus.Registerer) {
+	// RDS, Neptune and DocumentDB share one RDS client
+	rdsClient := get_rds_client(sess, region)
 
-				// Add all keys to the map. It is necessary to have every tag for the metric
-				for key, _ := range tags {
-					instances[*i.InstanceId][key] = ""
-				}
+	collectors := []struct {
+		service   string
+		collector Collector
+	}{
+		{"acmpca", collectorFunc(get_acm_pca_tags)},
+		{"ami", collectorFunc(get_ami_tags)},
+		{"amplify", collectorFunc(get_amplify_tags)},
+		{"apigateway", collectorFunc(get_apigateway_tags)},
+		{"apprunner", collectorFunc(get_apprunner_tags)},
+		{"appsync", collectorFunc(get_appsync_tags)},
+		{"asg", collectorFunc(get_asg_membership)},
+		{"backup", collectorFunc(get_backup_tags)},
+		{"batch", collectorFunc(get_batch_tags)},
+		{"cloudformation", collectorFunc(get_cloudformation_tags)},
+		{"cloudfront", collectorFunc(get_cloudfront_tags)},
+		{"cloudtrail", collectorFunc(get_cloudtrail_tags)},
+		{"cloudwatch", collectorFunc(get_cloudwatch_log_group_metrics)},
+		{"codebuild", collectorFunc(get_codebuild_tags)},
+		{"codedeploy", accountCollectorFunc{account, get_codedeploy_tags}},
+		{"codestarconnections", collectorFunc(get_codestar_connection_tags)},
+		{"cognito", collectorFunc(get_cognito_tags)},
+		{"comprehend", collectorFunc(get_comprehend_tags)},
+		{"connect", collectorFunc(get_connect_tags)},
+		{"cost", accountCollectorFunc{account, get_cost_metrics}},
+		{"datasync", collectorFunc(get_datasync_tags)},
+		{"directconnect", collectorFunc(get_directconnect_tags)},
+		{"documentdb", rdsCollectorFunc{rdsClient, get_documentdb_tags}},
+		{"ec2", collectorFunc(get_ec2_instance_tags)},
+		{"ecr", collectorFunc(get_ecr_tags)},
+		{"efs", collectorFunc(get_efs_tags)},
+		{"eip", collectorFunc(get_eip_tags)},
+		{"elasticbeanstalk", collectorFunc(get_elasticbeanstalk_tags)},
+		{"elb", collectorFunc(get_elb_membership)},
+		{"eni", collectorFunc(get_eni_tags)},
+		{"eventbridge", collectorFunc(get_eventbridge_tags)},
+		{"frauddetector", collectorFunc(get_frauddetector_tags)},
+		{"global_accelerator", collectorFunc(get_global_accelerator_tags)},
+		{"glue", accountCollectorFunc{account, get_glue_tags}},
+		{"groundtruth", collectorFunc(get_groundtruth_tags)},
+		{"guardduty", collectorFunc(get_guardduty_metrics)},
+		{"healthlake", collectorFunc(get_healthlake_tags)},
+		{"inspector", collectorFunc(get_inspector_metrics)},
+		{"iot", collectorFunc(get_iot_tags)},
+		{"ipam", collectorFunc(get_ipam_metrics)},
+		{"lakeformation", collectorFunc(get_lakeformation_tags)},
+		{"lambda", collectorFunc(get_lambda_tags)},
+		{"lightsail", collectorFunc(get_lightsail_tags)},
+		{"macie", collectorFunc(get_macie_tags)},
+		{"mediaconvert", collectorFunc(get_mediaconvert_tags)},
+		{"msk", collectorFunc(get_msk_tags)},
+		{"neptune", rdsCollectorFunc{rdsClient, get_neptune_tags}},
+		{"network_firewall", collectorFunc(get_network_firewall_tags)},
+		{"nlb", collectorFunc(get_nlb_target_metrics)},
+		{"organizations", collectorFunc(get_organizations_tags)},
+		{"outposts", collectorFunc(get_outposts_tags)},
+		{"pinpoint", collectorFunc(get_pinpoint_tags)},
+		{"placement_group", collectorFunc(get_placement_group_tags)},
+		{"ram", collectorFunc(get_ram_tags)},
+		{"rds", rdsCollectorFunc{rdsClient, get_rds_tags}},
+		{"rekognition", collectorFunc(get_rekognition_tags)},
+		{"reserved_instance", collectorFunc(get_reserved_instance_metrics)},
+		{"sagemaker", collectorFunc(get_sagemaker_tags)},
+		{"savingsplans", collectorFunc(get_savings_plan_metrics)},
+		{"security_group", collectorFunc(get_security_group_tags)},
+		{"securityhub", collectorFunc(get_securityhub_metrics)},
+		{"servicecatalog", collectorFunc(get_servicecatalog_tags)},
+		{"shield", collectorFunc(get_shield_tags)},
+		{"spot_request", collectorFunc(get_spot_request_tags)},
+		{"ssm", collectorFunc(get_ssm_maintenance_window_tags)},
+		{"stepfunctions", collectorFunc(get_stepfunctions_tags)},
+		{"subnet", collectorFunc(get_subnet_tags)},
+		{"timestream", collectorFunc(get_timestream_tags)},
+		{"transfer", collectorFunc(get_transfer_tags)},
+		{"transit_gateway", collectorFunc(get_transit_gateway_tags)},
+		{"trusted_advisor", collectorFunc(get_trusted_advisor_metrics)},
+		{"verified_access", collectorFunc(get_verified_access_tags)},
+		{"waf", collectorFunc(get_waf_tags)},
+		{"workspaces", collectorFunc(get_workspaces_tags)},
+	}
 
-				// Populate the instance's map with the tag values
-				if len(i.Tags) > 0 {
-					for _, t := range i.Tags {
-						instances[*i.InstanceId][*t.Key] = *t.Value
-					}
-				}
-			}
+	// A failing collector is reported and the others still run
+	for _, c := range collectors {
+		reg := &countingRegisterer{Registerer: registryFor(c.service)}
+		if err := c.collector.Collect(sess, region, reg); err != nil {
+			fmt.Println(err.Error())
+		}
+		if reg.results > 0 {
+			reportCount(c.service, region, reg.resources)
 		}
 	}
+}
 
-	// Create a string slice of keys for sorting
-	keys := make([]string, 0, len(tags)+1)
-	keys = append(keys, "InstanceId")
-	for k := range tags {
-		keys = append(keys, k)
+// Give a collector its own registry, gathered into the output file of its service
+// The account_id label is added when the account is known
+func new_service_registry(service string, accountId string) prometheus.Registerer {
+	reg := prometheus.NewRegistry()
+	var gatherer prometheus.Gatherer = reg
+	if accountId != "" {
+		gatherer = accountGatherer{accountId, reg}
 	}
-	sort.Strings(keys)
 
-	// Make sure all tag names are safe as Prometheus labels
-	// Specifically 'aws:autoscaling:groupName' is not valid
-	sanitizedKeys := make([]string, 0, len(keys))
-	for _, v := range keys {
-		sanitizeKey := sanatize_tag(v)
-		sanitizedKeys = append(sanitizedKeys, sanitizeKey)
+	// Discovered accounts collect in parallel
+	serviceGatherersMutex.Lock()
+	defer serviceGatherersMutex.Unlock()
+	serviceGatherers[service] = append(serviceGatherers[service], gatherer)
+	return reg
+}
+
+// Count and time every AWS API request, each page of a listing included
+func instrument_request(r *request.Request) {
+	apiCalls.WithLabelValues(r.ClientInfo.ServiceName, r.Operation.Name).Inc()
+	apiCallDuration.WithLabelValues(r.ClientInfo.ServiceName, r.Operation.Name).Observe(time.Since(r.Time).Seconds())
+}
+
+// Report the number of resources a collector discovered
+// Discovered accounts collect the same service and region, so their counts add up
+func reportCount(service, region string, n int) {
+	resourceCount.WithLabelValues(service, region).Add(float64(n))
+}
+
+// Registerer handed to each collector by collect_all, counting the resources of every CollectorResult
+// registered on it, so aws_resource_count covers every collector without each one reporting itself
+type countingRegisterer struct {
+	prometheus.Registerer
+	results   int
+	resources int
+}
+
+// Add the resources of a collector to its count, new_collector_result does this for every tag metric
+func count_resources(reg prometheus.Registerer, resources int) {
+	if c, ok := reg.(*countingRegisterer); ok {
+		c.results++
+		c.resources += resources
 	}
+}
 
-	// Create and register a new gauge for prometheus
-	ec2 := prometheus.NewGaugeVec(
+// The ARN partition of a region, aws-cn in China and aws-us-gov in GovCloud
+// Regions the SDK doesn't know yet are assumed to be in the standard partition
+func partition_for_region(region string) string {
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return partition.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// The partition of an ARN, falling back to the partition of the region for an ARN which can't be parsed
+func partition_for_arn(resourceArn string, region string) string {
+	if parsed, err := arn.Parse(resourceArn); err == nil {
+		return parsed.Partition
+	}
+	return partition_for_region(region)
+}
+
+// Record when the collection finished, so a stale metric file can be alerted on
+// Collectors report their own errors, so this is set even if some of them failed
+func set_last_collected() {
+	lastCollected := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Name: "aws_ec2_tags",
-			Help: "Key:Value metric per EC2 instances with all tags.",
+			Name: "aws_metrics_last_collected_timestamp_seconds",
+			Help: "Unix time at which the AWS metrics were last collected.",
 		},
-		sanitizedKeys,
 	)
-	registry.MustRegister(ec2)
-
-	// Build sort order []string for each instance
-	// Create one metric per instance with sort ordered labels
-	for key, value := range instances {
-		instanceString := make([]string, 0, len(keys))
-		for _, v := range keys {
-			if v == "InstanceId" {
-				instanceString = append(instanceString, key)
-			} else {
-				instanceString = append(instanceString, value[v])
-			}
-		}
-		ec2.WithLabelValues(instanceString...).Set(1)
-	}
+	registry.MustRegister(lastCollected)
+	lastCollected.Set(float64(time.Now().Unix()))
 }
 
-// Lists all EFS tags in us-west-2
-func get_efs_tags(region string) {
+// Initialize a session shared by every collector
+func new_session() *session.Session {
 	// Set up for a proxy, if one exists
 	httpclient := &http.Client{
 		Transport: &http.Transport{
@@ -355,39 +649,7450 @@ func get_efs_tags(region string) {
 		},
 	}
 
-	// Initialize a session
+	// AWS_PROFILE takes precedence over --profile
+	sessionProfile := profile
+	if _, ok := os.LookupEnv("AWS_PROFILE"); ok {
+		sessionProfile = ""
+	}
+
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
+		Profile:           sessionProfile,
+		Config: aws.Config{
+			Endpoint:   aws.String(endpointUrl),
+			HTTPClient: httpclient,
+		},
 	}))
+	// Copies of the session for other accounts keep the handler
+	sess.Handlers.Complete.PushBack(instrument_request)
+	return sess
+}
 
-	// Create EFS service client
-	svc := efs.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
-
-	result, err := svc.DescribeFileSystems(nil)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+// Build the sorted and sanitized label names from every resource and register a gauge with them
+// An empty idLabel means the resource id is not a label and every label is in the resource map
+func new_collector_result(reg prometheus.Registerer, name string, help string, idLabel string, resources map[string]map[string]string) *CollectorResult {
+	// Create a string slice of keys for sorting
+	labelNames := make(map[string]string)
+	if idLabel != "" {
+		labelNames[idLabel] = ""
 	}
-
-	// Iterate through all the filesystems, gather the tag names and add them to the tags map
-	tags := make(map[string]string)
-	for _, f := range result.FileSystems {
-		// Create input for DescribeTags method
-		fileSystemId := aws.StringValue(f.FileSystemId)
-		input := &efs.DescribeTagsInput{
-			FileSystemId: aws.String(fileSystemId),
+	for _, labels := range resources {
+		for k := range labels {
+			labelNames[k] = ""
+		}
+	}
+	keys := make([]string, 0, len(labelNames))
+	for k := range labelNames {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Make sure all tag names are safe as Prometheus labels
+	sanitizedKeys := label_names(keys)
+
+	// Create and register a new gauge for prometheus
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		sanitizedKeys,
+	)
+	reg.MustRegister(gauge)
+	count_resources(reg, len(resources))
+
+	// The resource type is the service part of the metric name, aws_ec2_tags becomes ec2
+	if len(requiredTags) > 0 {
+		resourceType := strings.TrimSuffix(strings.TrimPrefix(name, "aws_"), "_tags")
+		set_missing_required_tags(reg, resourceType, resources)
+	}
+
+	return &CollectorResult{
+		Gauge:   gauge,
+		Labels:  resources,
+		idLabel: idLabel,
+		keys:    keys,
+	}
+}
+
+// Count the required tags each resource is missing, a tag with an empty value counts as missing
+func set_missing_required_tags(reg prometheus.Registerer, resourceType string, resources map[string]map[string]string) {
+	// Every collector shares one gauge per registry
+	missing := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_resource_missing_required_tags",
+			Help: "Number of required tags missing from each resource, 0 if the resource is compliant.",
+		},
+		[]string{"resource_type", "resource_id", "missing_tags"},
+	)
+	if err := reg.Register(missing); err != nil {
+		existing, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			panic(err)
+		}
+		missing = existing.ExistingCollector.(*prometheus.GaugeVec)
+	}
+
+	for id, labels := range resources {
+		absent := make([]string, 0, len(requiredTags))
+		for _, t := range requiredTags {
+			if labels[t] == "" {
+				absent = append(absent, t)
+			}
+		}
+		missing.WithLabelValues(resourceType, id, strings.Join(absent, ",")).Set(float64(len(absent)))
+	}
+}
+
+// Set the metric of one resource with its labels in sort order
+func (r *CollectorResult) Set(id string, value float64) {
+	values := make([]string, 0, len(r.keys))
+	for _, v := range r.keys {
+		if v == r.idLabel {
+			values = append(values, id)
+		} else {
+			labelValue := truncateValue(r.Labels[id][v], labelValueMaxLength)
+			if labelValue != r.Labels[id][v] {
+				log_truncated(v, id, r.Labels[id][v])
+			}
+			values = append(values, labelValue)
+		}
+	}
+	r.Gauge.WithLabelValues(values...).Set(value)
+}
+
+// Report a truncated label value, the original value is only logged with --verbose as it is what was too long
+func log_truncated(label string, id string, value string) {
+	if verbose {
+		log.Printf("DEBUG: Truncated label '%s' of '%s', the value was '%s'", label, id, value)
+		return
+	}
+	truncatedLabelsMutex.Lock()
+	defer truncatedLabelsMutex.Unlock()
+	if !truncatedLabels[label] {
+		truncatedLabels[label] = true
+		log.Printf("WARNING: Truncated values of label '%s' longer than %d characters, pass --verbose to log each one", label, labelValueMaxLength)
+	}
+}
+
+// Truncate a label value to max characters and suffix it with ..., a max of 0 or less is unlimited
+// Characters are counted as runes so a multi-byte character is never cut into invalid UTF-8
+func truncateValue(s string, max int) string {
+	runes := []rune(s)
+	if max <= 0 || len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
+
+// Create the prometheus regestry
+var (
+	registry = prometheus.NewRegistry()
+)
+
+// Count and time every AWS API call made by the collectors
+var (
+	apiCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aws_api_calls_total",
+			Help: "Number of AWS API calls made per service and operation.",
+		},
+		[]string{"service", "operation"},
+	)
+	apiCallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aws_api_call_duration_seconds",
+			Help:    "Round-trip latency of AWS API calls per service and operation.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"service", "operation"},
+	)
+)
+
+// Number of resources found per service and region, set by collect_all with reportCount
+var (
+	resourceCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_resource_count",
+			Help: "Number of resources discovered per service and region.",
+		},
+		[]string{"service", "region"},
+	)
+)
+
+// Shared config profile to use for every session, set with --profile
+var (
+	profile = ""
+)
+
+// Tags every resource must have, set with --required-tags
+var (
+	requiredTags = make([]string, 0)
+)
+
+// Help text per metric name, overriding the defaults, set with --metric-help-file
+var (
+	metricHelp = make(map[string]string)
+)
+
+// Registries of the collected accounts, every account found with --discover-accounts or the account of the session
+var (
+	accountGatherers = make([]prometheus.Gatherer, 0)
+)
+
+// Label names of tag keys, set with --tag-rename
+var (
+	tagRenames = make(tagRenameFlag)
+)
+
+// Longest tag label value before it is truncated, set with --label-value-max-length
+// Truncation is logged once per label, collectors of discovered accounts run in parallel
+var (
+	labelValueMaxLength  = 0
+	truncatedLabels      = make(map[string]bool)
+	truncatedLabelsMutex sync.Mutex
+)
+
+// Log every truncated label value in full, set with --verbose
+var (
+	verbose = false
+)
+
+// Registries of every service, set with --split-by-service
+var (
+	splitByService        = false
+	serviceGatherers      = make(map[string]prometheus.Gatherers)
+	serviceGatherersMutex sync.Mutex
+)
+
+// Resource filters, set with --ec2-filter, --asg-filter and --elb-filter
+var (
+	ec2Filters  = make(filterFlag, 0)
+	asgFilters  = make(filterFlag, 0)
+	elbFilters  = make(filterFlag, 0)
+	filterRegex = regexp.MustCompile("^Name=([^,=]+),Values=(.+)$")
+)
+
+// Override the AWS API endpoint for every service, e.g. to point at LocalStack
+var (
+	endpointUrl = os.Getenv("AWS_ENDPOINT_URL")
+)
+
+// Load the help text overrides, the file maps metric names to help strings:
+// aws_ec2_tags: "EC2 instances of the web tier, owned by the platform team"
+func load_metric_help(file string) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := yaml.Unmarshal(data, &metricHelp); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Gather all prometheus metrics from the gatherer
+func prometheus_gather(gatherer prometheus.Gatherer, format string) string {
+	gathering, err := gatherer.Gather()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Replace the default help text with the configured one
+	for _, mf := range gathering {
+		if help, ok := metricHelp[mf.GetName()]; ok {
+			mf.Help = aws.String(help)
+		}
+	}
+
+	// Create the output buffer and write out all of the gathered metrics
+	out := &bytes.Buffer{}
+	if format == "protobuf" {
+		encoder := expfmt.NewEncoder(out, expfmt.FmtProtoDelim)
+		for _, mf := range gathering {
+			if err := encoder.Encode(mf); err != nil {
+				panic(err)
+			}
+		}
+		return out.String()
+	}
+	for _, mf := range gathering {
+		if _, err := expfmt.MetricFamilyToText(out, mf); err != nil {
+			panic(err)
+		}
+	}
+	//    fmt.Print(out.String())
+	metricsString := out.String()
+	return metricsString
+}
+
+// Metric labels allow only "^[a-zA-Z_][a-zA-Z0-9_]*$"
+// Need to fix tags like: 'aws:autoscaling:groupName'
+// Compiled once, sanatize_tag runs for every tag of every resource
+var (
+	regex_full          = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+	regex_first_number  = regexp.MustCompile("^[0-9_]*")
+	regex_valid_segment = regexp.MustCompile("[a-zA-Z0-9_]*")
+)
+
+// Ensure all Prometheus labels are valid
+func sanatize_tag(tag string) string {
+	// Check tag against regex and return if it passes
+	if regex_full.MatchString(tag) {
+		return tag
+	}
+
+	// Slice the string keeping only valid portions
+	valid_slice := regex_valid_segment.FindAllStringSubmatch(tag, -1)
+	restitch := make([]string, 0, len(valid_slice)+1)
+	for i, v := range valid_slice {
+		restitch = append(restitch, strings.Join(v[:], ""))
+		if i < len(valid_slice)-1 {
+			restitch = append(restitch, "_")
+		}
+	}
+	// Restitch string togeather with underscore
+	// Validate and return
+	valid_string := strings.Join(restitch[:], "")
+	if regex_full.MatchString(valid_string) {
+		return valid_string
+	}
+
+	// Test for the first character matching [0-9] and pad with underscore
+	// Validate and retrune else panic
+	pad_slice := make([]string, 0, len(valid_string)+1)
+	if regex_first_number.MatchString(valid_string) {
+		pad_slice = append(pad_slice, "_", valid_string)
+	}
+	pad_string := strings.Join(pad_slice[:], "")
+	if regex_full.MatchString(pad_string) {
+		return pad_string
+	} else {
+		errorString := ("Can not fix tag '" + tag + "' to validate against regex '^[a-zA-Z][a-zA-Z0-9_]*$'")
+		panic(errorString)
+	}
+}
+
+// Turn tag keys into unique label names in the same order, tags renamed with --tag-rename
+// go first so they keep their name and any sanitized tag colliding with them is suffixed instead
+func label_names(keys []string) []string {
+	ordered := make([]string, 0, len(keys))
+	for _, v := range keys {
+		if _, ok := tagRenames[v]; ok {
+			ordered = append(ordered, v)
+		}
+	}
+	for _, v := range keys {
+		if _, ok := tagRenames[v]; !ok {
+			ordered = append(ordered, v)
+		}
+	}
+
+	// The account_id label is added when gathering, so it goes first and a tag by that name is suffixed
+	labels := []string{accountIdLabel}
+	for _, v := range ordered {
+		if label, ok := tagRenames[v]; ok {
+			labels = append(labels, label)
+		} else {
+			labels = append(labels, sanatize_tag(v))
+		}
+	}
+	labels = dedupe_labels(labels)[1:]
+
+	// Put the label names back in the order of the keys
+	names := make(map[string]string)
+	for i, v := range ordered {
+		names[v] = labels[i]
+	}
+	sanitizedKeys := make([]string, 0, len(keys))
+	for _, v := range keys {
+		sanitizedKeys = append(sanitizedKeys, names[v])
+	}
+	return sanitizedKeys
+}
+
+// Tags like 'team:name' and 'team-name' sanitize to the same label name
+// Suffix the second occurrence with _2, the third with _3 and so on
+func dedupe_labels(labels []string) []string {
+	used := make(map[string]bool)
+	count := make(map[string]int)
+	deduped := make([]string, 0, len(labels))
+	for _, v := range labels {
+		count[v]++
+		label := v
+		for n := count[v]; used[label]; n++ {
+			label = fmt.Sprintf("%s_%d", v, n)
+		}
+		used[label] = true
+		deduped = append(deduped, label)
+	}
+	return deduped
+}
+
+func write_file(outFile string, fileContents string, format string) {
+	// The binary format gets its own extension unless a custom one was given
+	if format == "protobuf" && filepath.Ext(outFile) == ".prom" {
+		outFile = strings.TrimSuffix(outFile, ".prom") + ".pb"
+	}
+
+	dir, file := filepath.Split(outFile)
+	s1 := rand.NewSource(time.Now().UnixNano())
+	r1 := rand.New(s1)
+	tmpName := filepath.Join(dir, fmt.Sprintf("%s.tmp%d", file, r1.Intn(10000)))
+
+	// Create the output directory if it does not exist yet
+	if dir != "" {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("WARNING: Created missing output directory '%s'", dir)
+		}
+	}
+
+	tmpFile, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer os.Remove(tmpName)
+
+	if _, err := tmpFile.Write([]byte(fileContents)); err != nil {
+		log.Fatal(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.Rename(tmpName, outFile); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Convert gathered metric families into remote write time series, every sample gets the same timestamp
+// Histograms and summaries are split into their _bucket, _sum and _count series like in the text format
+func to_timeseries(mfs []*dto.MetricFamily, timestamp int64) []prompb.TimeSeries {
+	series := make([]prompb.TimeSeries, 0)
+	add := func(name string, m *dto.Metric, extra *prompb.Label, value float64) {
+		labels := []prompb.Label{{Name: "__name__", Value: name}}
+		for _, l := range m.Label {
+			labels = append(labels, prompb.Label{Name: l.GetName(), Value: l.GetValue()})
+		}
+		if extra != nil {
+			labels = append(labels, *extra)
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			return labels[i].Name < labels[j].Name
+		})
+		series = append(series, prompb.TimeSeries{
+			Labels:  labels,
+			Samples: []prompb.Sample{{Value: value, Timestamp: timestamp}},
+		})
+	}
+
+	for _, mf := range mfs {
+		name := mf.GetName()
+		for _, m := range mf.Metric {
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m, nil, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m, nil, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m, nil, m.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.Bucket {
+					le := strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64)
+					add(name+"_bucket", m, &prompb.Label{Name: "le", Value: le}, float64(b.GetCumulativeCount()))
+				}
+				add(name+"_bucket", m, &prompb.Label{Name: "le", Value: "+Inf"}, float64(h.GetSampleCount()))
+				add(name+"_sum", m, nil, h.GetSampleSum())
+				add(name+"_count", m, nil, float64(h.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				sm := m.GetSummary()
+				for _, q := range sm.Quantile {
+					quantile := strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)
+					add(name, m, &prompb.Label{Name: "quantile", Value: quantile}, q.GetValue())
+				}
+				add(name+"_sum", m, nil, sm.GetSampleSum())
+				add(name+"_count", m, nil, float64(sm.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+// Push every gathered metric to a Prometheus remote write endpoint such as Mimir, Cortex or Thanos
+// tlsCert is a PEM file holding both the client certificate and its key
+func remote_write(gatherer prometheus.Gatherer, endpoint string, bearerToken string, tlsCert string) error {
+	mfs, err := gatherer.Gather()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// The remote write body is a snappy compressed WriteRequest protobuf
+	writeRequest := &prompb.WriteRequest{
+		Timeseries: to_timeseries(mfs, time.Now().UnixNano()/int64(time.Millisecond)),
+	}
+	data, err := writeRequest.Marshal()
+	if err != nil {
+		return err
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if tlsCert != "" {
+		pem, err := ioutil.ReadFile(tlsCert)
+		if err != nil {
+			return err
+		}
+		cert, err := tls.X509KeyPair(pem, pem)
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "nubis-prometheus-exposition")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("remote write to %s failed with %s: %s", endpoint, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Lists all ACM Private CA certificate authority tags in us-west-2
+func get_acm_pca_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create ACM PCA service client
+	svc := acmpca.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the certificate authorities, the response holds the same details as DescribeCertificateAuthority
+	authorities := make([]*acmpca.CertificateAuthority, 0)
+	err := svc.ListCertificateAuthoritiesPages(&acmpca.ListCertificateAuthoritiesInput{},
+		func(page *acmpca.ListCertificateAuthoritiesOutput, lastPage bool) bool {
+			authorities = append(authorities, page.CertificateAuthorities...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the certificate authorities, gather the tag names and add them to the tags map
+	// Keep the tags for each certificate authority so we only list them once
+	tags := make(map[string]string)
+	authorityTags := make(map[string][]*acmpca.Tag)
+	for _, f := range authorities {
+		err := svc.ListTagsPages(&acmpca.ListTagsInput{
+			CertificateAuthorityArn: f.Arn,
+		},
+			func(page *acmpca.ListTagsOutput, lastPage bool) bool {
+				authorityTags[*f.Arn] = append(authorityTags[*f.Arn], page.Tags...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		// If the key is not in the map, add it
+		for _, v := range authorityTags[*f.Arn] {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each certificate authority and pupulate authority map
+	authority := make(map[string]map[string]string)
+	for _, f := range authorities {
+		// The configuration is optional, default the key algorithm to empty
+		keyAlgorithm := ""
+		if f.CertificateAuthorityConfiguration != nil {
+			keyAlgorithm = aws.StringValue(f.CertificateAuthorityConfiguration.KeyAlgorithm)
+		}
+
+		// Initialize the map for this certificate authority
+		authority[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			authority[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		authority[*f.Arn]["Type"] = aws.StringValue(f.Type)
+		authority[*f.Arn]["Status"] = aws.StringValue(f.Status)
+		authority[*f.Arn]["KeyAlgorithm"] = keyAlgorithm
+
+		// Populate the certificate authority's map with the tag values
+		for _, t := range authorityTags[*f.Arn] {
+			authority[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, active certificate authorities are 1 and all others are 0
+	authorityGauge := new_collector_result(reg, "aws_acm_pca_tags", "Key:Value metric per ACM Private CA certificate authority with all tags, 1 if the certificate authority is active.", "Arn", authority)
+	for _, f := range authorities {
+		if aws.StringValue(f.Status) == acmpca.CertificateAuthorityStatusActive {
+			authorityGauge.Set(*f.Arn, 1)
+		} else {
+			authorityGauge.Set(*f.Arn, 0)
+		}
+	}
+
+	// Create and register a new gauge for the expiry of each certificate authority
+	expiry := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_acm_pca_expiry_seconds",
+			Help: "Unix timestamp at which the ACM Private CA certificate authority certificate expires.",
+		},
+		[]string{"Arn"},
+	)
+	reg.MustRegister(expiry)
+	for _, f := range authorities {
+		// Certificate authorities waiting for their certificate have no expiry yet
+		if f.NotAfter == nil {
+			continue
+		}
+		expiry.WithLabelValues(aws.StringValue(f.Arn)).Set(float64(f.NotAfter.Unix()))
+	}
+	return nil
+}
+
+// Lists all tags of the AMIs owned by the account in us-west-2
+func get_ami_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the images owned by the account, their tags are part of the response
+	images := make([]*ec2.Image, 0)
+	err := svc.DescribeImagesPages(&ec2.DescribeImagesInput{
+		Owners: aws.StringSlice([]string{"self"}),
+	},
+		func(page *ec2.DescribeImagesOutput, lastPage bool) bool {
+			images = append(images, page.Images...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the images, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range images {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each image and pupulate image map
+	image := make(map[string]map[string]string)
+	for _, f := range images {
+		// Initialize the map for this image
+		image[*f.ImageId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			image[*f.ImageId][key] = ""
+		}
+
+		// Add metadata as tags
+		image[*f.ImageId]["Name"] = aws.StringValue(f.Name)
+		image[*f.ImageId]["State"] = aws.StringValue(f.State)
+		image[*f.ImageId]["Architecture"] = aws.StringValue(f.Architecture)
+		image[*f.ImageId]["VirtualizationType"] = aws.StringValue(f.VirtualizationType)
+		image[*f.ImageId]["RootDeviceType"] = aws.StringValue(f.RootDeviceType)
+
+		// Populate the image's map with the tag values
+		for _, t := range f.Tags {
+			image[*f.ImageId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, available images are 1 and pending or failed ones are 0
+	imageGauge := new_collector_result(reg, "aws_ami_tags", "Key:Value metric per AMI owned by the account with all tags, 1 if the image is available.", "ImageId", image)
+	for _, f := range images {
+		if aws.StringValue(f.State) == ec2.ImageStateAvailable {
+			imageGauge.Set(*f.ImageId, 1)
+		} else {
+			imageGauge.Set(*f.ImageId, 0)
+		}
+	}
+
+	// Create and register a new gauge for the creation date of each image
+	creation := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ami_creation_timestamp",
+			Help: "Unix timestamp at which the AMI was created.",
+		},
+		[]string{"ImageId"},
+	)
+	reg.MustRegister(creation)
+	for _, f := range images {
+		// The creation date is sent as an ISO 8601 string
+		if f.CreationDate == nil {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, *f.CreationDate)
+		if err != nil {
+			return err
+		}
+		creation.WithLabelValues(aws.StringValue(f.ImageId)).Set(float64(created.Unix()))
+	}
+	return nil
+}
+
+// Lists all Amplify app and branch tags in us-west-2
+func get_amplify_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Amplify service client
+	svc := amplify.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the apps, their tags are part of the response
+	apps := make([]*amplify.App, 0)
+	err := svc.ListAppsPages(&amplify.ListAppsInput{},
+		func(page *amplify.ListAppsOutput, lastPage bool) bool {
+			apps = append(apps, page.Apps...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the apps, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range apps {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each app and pupulate app map
+	app := make(map[string]map[string]string)
+	for _, f := range apps {
+		// Initialize the map for this app
+		app[*f.AppId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			app[*f.AppId][key] = ""
+		}
+
+		// Add metadata as tags
+		app[*f.AppId]["Name"] = aws.StringValue(f.Name)
+		app[*f.AppId]["Platform"] = aws.StringValue(f.Platform)
+		app[*f.AppId]["Repository"] = aws.StringValue(f.Repository)
+
+		// Populate the app's map with the tag values
+		for k, v := range f.Tags {
+			app[*f.AppId][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per app
+	appGauge := new_collector_result(reg, "aws_amplify_app_tags", "Key:Value metric per Amplify app with all tags.", "AppId", app)
+	for key := range app {
+		appGauge.Set(key, 1)
+	}
+
+	// Page through the branches of each app
+	branches := make([]*amplify.Branch, 0)
+	branchApps := make(map[string]string)
+	for _, a := range apps {
+		err := svc.ListBranchesPages(&amplify.ListBranchesInput{
+			AppId: a.AppId,
+		},
+			func(page *amplify.ListBranchesOutput, lastPage bool) bool {
+				for _, b := range page.Branches {
+					branchApps[*b.BranchArn] = aws.StringValue(a.AppId)
+				}
+				branches = append(branches, page.Branches...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate through all the branches, gather the tag names and add them to the tags map
+	// Keep the tags for each branch so we only list them once
+	branchTagKeys := make(map[string]string)
+	branchTags := make(map[string]map[string]*string)
+	for _, f := range branches {
+		// Create input for ListTagsForResource method
+		input := &amplify.ListTagsForResourceInput{
+			ResourceArn: f.BranchArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		branchTags[*f.BranchArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := branchTagKeys[k]; !ok {
+				branchTagKeys[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each branch and pupulate branch map
+	// Branch names are only unique within an app so the AppId is a label too
+	branch := make(map[string]map[string]string)
+	for _, f := range branches {
+		// Initialize the map for this branch
+		branch[*f.BranchArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range branchTagKeys {
+			branch[*f.BranchArn][key] = ""
+		}
+
+		// Add metadata as tags
+		branch[*f.BranchArn]["AppId"] = branchApps[*f.BranchArn]
+		branch[*f.BranchArn]["BranchName"] = aws.StringValue(f.BranchName)
+		branch[*f.BranchArn]["Stage"] = aws.StringValue(f.Stage)
+		branch[*f.BranchArn]["Framework"] = aws.StringValue(f.Framework)
+		branch[*f.BranchArn]["ActiveJobId"] = aws.StringValue(f.ActiveJobId)
+
+		// Populate the branch's map with the tag values
+		for k, v := range branchTags[*f.BranchArn] {
+			branch[*f.BranchArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per branch
+	branchGauge := new_collector_result(reg, "aws_amplify_branch_tags", "Key:Value metric per Amplify branch with all tags.", "", branch)
+	for key := range branch {
+		branchGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all API Gateway REST API stage tags in us-west-2
+func get_apigateway_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create API Gateway service client
+	svc := apigateway.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Stage ARNs are built by hand, so they need the partition of the region
+	partition := partition_for_region(region)
+
+	// Page through all of the REST APIs
+	restApis := make([]*apigateway.RestApi, 0)
+	err := svc.GetRestApisPages(&apigateway.GetRestApisInput{},
+		func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+			restApis = append(restApis, page.Items...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the stages of every API, gather the tag names and add them to the tags map
+	// Keep the tags for each stage so we only look them up once
+	tags := make(map[string]string)
+	stage := make(map[string]map[string]string)
+	stageTags := make(map[string]map[string]*string)
+	cacheEnabled := make(map[string]bool)
+	for _, f := range restApis {
+		resultStages, err := svc.GetStages(&apigateway.GetStagesInput{
+			RestApiId: f.Id,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, s := range resultStages.Item {
+			// Create input for GetTags method
+			arn := fmt.Sprintf("arn:%s:apigateway:%s::/restapis/%s/stages/%s", partition, region, aws.StringValue(f.Id), aws.StringValue(s.StageName))
+			input := &apigateway.GetTagsInput{
+				ResourceArn: aws.String(arn),
+			}
+
+			// List out the tags
+			resultTags, err := svc.GetTags(input)
+			if err != nil {
+				return err
+			}
+			stageTags[arn] = resultTags.Tags
+			cacheEnabled[arn] = aws.BoolValue(s.CacheClusterEnabled)
+
+			// If the key is not in the map, add it
+			for k, _ := range resultTags.Tags {
+				if _, ok := tags[k]; !ok {
+					tags[k] = ""
+				}
+			}
+
+			// Initialize the map for this stage and add metadata as tags
+			stage[arn] = make(map[string]string)
+			stage[arn]["RestApiId"] = aws.StringValue(f.Id)
+			stage[arn]["ApiName"] = aws.StringValue(f.Name)
+			stage[arn]["StageName"] = aws.StringValue(s.StageName)
+			stage[arn]["Description"] = aws.StringValue(s.Description)
+		}
+	}
+
+	// Populate each stage's map with every tag key and its tag values
+	for arn, value := range stage {
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			value[key] = ""
+		}
+
+		// Populate the stage's map with the tag values
+		for k, v := range stageTags[arn] {
+			value[k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag
+	stageGauge := new_collector_result(reg, "aws_apigateway_stage_tags", "Key:Value metric per API Gateway REST API stage with all tags.", "", stage)
+
+	// Create and register a new gauge for the stage cache setting
+	cacheGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_apigateway_stage_cache_enabled",
+			Help: "1 if caching is enabled on the API Gateway REST API stage, 0 otherwise.",
+		},
+		[]string{"RestApiId", "ApiName", "StageName"},
+	)
+	reg.MustRegister(cacheGauge)
+
+	// Create one metric per stage
+	for key, value := range stage {
+		stageGauge.Set(key, 1)
+
+		if cacheEnabled[key] {
+			cacheGauge.WithLabelValues(value["RestApiId"], value["ApiName"], value["StageName"]).Set(1)
+		} else {
+			cacheGauge.WithLabelValues(value["RestApiId"], value["ApiName"], value["StageName"]).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all App Runner service tags in us-west-2
+// ListServices already has the service metadata, the tags are looked up per service
+func get_apprunner_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create App Runner service client
+	svc := apprunner.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the services
+	services := make([]*apprunner.ServiceSummary, 0)
+	err := svc.ListServicesPages(&apprunner.ListServicesInput{},
+		func(page *apprunner.ListServicesOutput, lastPage bool) bool {
+			services = append(services, page.ServiceSummaryList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the services, gather the tag names and add them to the tags map
+	// Keep the tags for each service so we only list them once
+	tags := make(map[string]string)
+	serviceTags := make(map[string][]*apprunner.Tag)
+	for _, f := range services {
+		// Create input for ListTagsForResource method
+		input := &apprunner.ListTagsForResourceInput{
+			ResourceArn: f.ServiceArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		serviceTags[*f.ServiceArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each service and pupulate service map
+	service := make(map[string]map[string]string)
+	for _, f := range services {
+		// Initialize the map for this service
+		service[*f.ServiceArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			service[*f.ServiceArn][key] = ""
+		}
+
+		// Add metadata as tags
+		service[*f.ServiceArn]["ServiceName"] = aws.StringValue(f.ServiceName)
+		service[*f.ServiceArn]["Status"] = aws.StringValue(f.Status)
+		service[*f.ServiceArn]["ServiceUrl"] = aws.StringValue(f.ServiceUrl)
+
+		// Populate the service's map with the tag values
+		for _, t := range serviceTags[*f.ServiceArn] {
+			service[*f.ServiceArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per service
+	serviceGauge := new_collector_result(reg, "aws_apprunner_service_tags", "Key:Value metric per App Runner service with all tags. 1 if RUNNING, 0 otherwise.", "ServiceArn", service)
+	for key, value := range service {
+		if value["Status"] == apprunner.ServiceStatusRunning {
+			serviceGauge.Set(key, 1)
+		} else {
+			serviceGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all AppSync GraphQL API tags and cache settings in us-west-2
+func get_appsync_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create AppSync service client
+	svc := appsync.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the GraphQL APIs
+	graphqlApis := make([]*appsync.GraphqlApi, 0)
+	err := svc.ListGraphqlApisPages(&appsync.ListGraphqlApisInput{},
+		func(page *appsync.ListGraphqlApisOutput, lastPage bool) bool {
+			graphqlApis = append(graphqlApis, page.GraphqlApis...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the GraphQL APIs, gather the tag names and add them to the tags map
+	// Keep the tags for each GraphQL API so we only list them once
+	tags := make(map[string]string)
+	apiTags := make(map[string]map[string]*string)
+	for _, f := range graphqlApis {
+		// Create input for ListTagsForResource method
+		input := &appsync.ListTagsForResourceInput{
+			ResourceArn: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		apiTags[*f.ApiId] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each GraphQL API and pupulate GraphQL API map
+	graphqlApi := make(map[string]map[string]string)
+	for _, f := range graphqlApis {
+		// Initialize the map for this GraphQL API
+		graphqlApi[*f.ApiId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			graphqlApi[*f.ApiId][key] = ""
+		}
+
+		// Add metadata as tags
+		graphqlApi[*f.ApiId]["Name"] = aws.StringValue(f.Name)
+		graphqlApi[*f.ApiId]["AuthenticationType"] = aws.StringValue(f.AuthenticationType)
+		graphqlApi[*f.ApiId]["ApiType"] = aws.StringValue(f.ApiType)
+
+		// Populate the GraphQL API's map with the tag values
+		for k, v := range apiTags[*f.ApiId] {
+			graphqlApi[*f.ApiId][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per GraphQL API
+	apiGauge := new_collector_result(reg, "aws_appsync_api_tags", "Key:Value metric per AppSync GraphQL API with all tags.", "ApiId", graphqlApi)
+	for key := range graphqlApi {
+		apiGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the cache setting of each API
+	cacheEnabled := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_appsync_api_cache_enabled",
+			Help: "1 if caching is configured on the AppSync GraphQL API, 0 otherwise.",
+		},
+		[]string{"ApiId", "Name"},
+	)
+	reg.MustRegister(cacheEnabled)
+
+	// An API without a cache returns a NotFoundException
+	for _, f := range graphqlApis {
+		_, err := svc.GetApiCache(&appsync.GetApiCacheInput{
+			ApiId: f.ApiId,
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == appsync.ErrCodeNotFoundException {
+				cacheEnabled.WithLabelValues(aws.StringValue(f.ApiId), aws.StringValue(f.Name)).Set(0)
+				continue
+			}
+			return err
+		}
+		cacheEnabled.WithLabelValues(aws.StringValue(f.ApiId), aws.StringValue(f.Name)).Set(1)
+	}
+	return nil
+}
+
+// Lists all instances in an ASG in us-west-2
+func get_asg_membership(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create AutoScaling service client
+	svc := autoscaling.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Only describe the groups matching --asg-filter and --asg-tag-filter, the API ANDs the filters
+	filters := make([]*autoscaling.Filter, 0, len(asgFilters))
+	for _, f := range asgFilters {
+		filters = append(filters, &autoscaling.Filter{
+			Name:   aws.String(f.Name),
+			Values: aws.StringSlice(f.Values),
+		})
+	}
+
+	result, err := svc.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for prometheus
+	asg := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_asg_instances",
+			Help: "Metric per EC2 instances within an ASG.",
+		},
+		[]string{"AutoScalingGroupName", "AutoScalingGroupARN", "InstanceId"},
+	)
+	reg.MustRegister(asg)
+
+	// Iterate through all groups, gather instances adding a metric for each
+	for _, f := range result.AutoScalingGroups {
+		for _, v := range f.Instances {
+			asg.WithLabelValues(aws.StringValue(f.AutoScalingGroupName), aws.StringValue(f.AutoScalingGroupARN), *v.InstanceId).Set(1)
+		}
+	}
+
+	// Create and register new gauges for the launch template or launch configuration of each group
+	launchTemplate := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_asg_launch_template",
+			Help: "1 if the ASG uses a launch template, 0 if it uses a launch configuration.",
+		},
+		[]string{"AutoScalingGroupName", "LaunchTemplateName", "LaunchTemplateId", "LaunchTemplateVersion"},
+	)
+	launchConfiguration := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_asg_launch_configuration",
+			Help: "Metric per ASG still using a deprecated launch configuration.",
+		},
+		[]string{"AutoScalingGroupName", "LaunchConfigurationName"},
+	)
+	reg.MustRegister(launchTemplate, launchConfiguration)
+	for _, f := range result.AutoScalingGroups {
+		// Groups with a mixed instances policy keep their launch template in the policy
+		template := f.LaunchTemplate
+		if template == nil && f.MixedInstancesPolicy != nil && f.MixedInstancesPolicy.LaunchTemplate != nil {
+			template = f.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification
+		}
+
+		if template != nil {
+			launchTemplate.WithLabelValues(aws.StringValue(f.AutoScalingGroupName), aws.StringValue(template.LaunchTemplateName), aws.StringValue(template.LaunchTemplateId), aws.StringValue(template.Version)).Set(1)
+		} else if f.LaunchConfigurationName != nil {
+			launchTemplate.WithLabelValues(aws.StringValue(f.AutoScalingGroupName), "", "", "").Set(0)
+			launchConfiguration.WithLabelValues(aws.StringValue(f.AutoScalingGroupName), aws.StringValue(f.LaunchConfigurationName)).Set(1)
+		}
+	}
+	count_resources(reg, len(result.AutoScalingGroups))
+	return nil
+}
+
+// Lists all Backup plan and vault tags in us-west-2
+func get_backup_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Backup service client
+	svc := backup.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the backup plans
+	plans := make([]*backup.PlansListMember, 0)
+	err := svc.ListBackupPlansPages(&backup.ListBackupPlansInput{},
+		func(page *backup.ListBackupPlansOutput, lastPage bool) bool {
+			plans = append(plans, page.BackupPlansList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the backup plans, gather the tag names and add them to the tags map
+	// Keep the tags for each backup plan so we only list them once
+	tags := make(map[string]string)
+	planTags := make(map[string]map[string]*string)
+	for _, f := range plans {
+		resultTags, err := list_backup_tags(svc, f.BackupPlanArn)
+		if err != nil {
+			return err
+		}
+		planTags[*f.BackupPlanArn] = resultTags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each backup plan and pupulate backup plan map
+	plan := make(map[string]map[string]string)
+	for _, f := range plans {
+		// Initialize the map for this backup plan
+		plan[*f.BackupPlanArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			plan[*f.BackupPlanArn][key] = ""
+		}
+
+		// Add metadata as tags
+		plan[*f.BackupPlanArn]["BackupPlanId"] = aws.StringValue(f.BackupPlanId)
+		plan[*f.BackupPlanArn]["BackupPlanName"] = aws.StringValue(f.BackupPlanName)
+		plan[*f.BackupPlanArn]["VersionId"] = aws.StringValue(f.VersionId)
+
+		// Populate the backup plan's map with the tag values
+		for k, v := range planTags[*f.BackupPlanArn] {
+			plan[*f.BackupPlanArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per backup plan
+	planGauge := new_collector_result(reg, "aws_backup_plan_tags", "Key:Value metric per Backup plan with all tags.", "BackupPlanArn", plan)
+	for key := range plan {
+		planGauge.Set(key, 1)
+	}
+
+	// Page through all of the backup vaults
+	vaults := make([]*backup.VaultListMember, 0)
+	err = svc.ListBackupVaultsPages(&backup.ListBackupVaultsInput{},
+		func(page *backup.ListBackupVaultsOutput, lastPage bool) bool {
+			vaults = append(vaults, page.BackupVaultList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the backup vaults, gather the tag names and add them to the vaultTagNames map
+	// Keep the tags for each backup vault so we only list them once
+	vaultTagNames := make(map[string]string)
+	vaultTags := make(map[string]map[string]*string)
+	for _, f := range vaults {
+		resultTags, err := list_backup_tags(svc, f.BackupVaultArn)
+		if err != nil {
+			return err
+		}
+		vaultTags[*f.BackupVaultArn] = resultTags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags {
+			if _, ok := vaultTagNames[k]; !ok {
+				vaultTagNames[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each backup vault and pupulate backup vault map
+	vault := make(map[string]map[string]string)
+	for _, f := range vaults {
+		// Initialize the map for this backup vault
+		vault[*f.BackupVaultArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range vaultTagNames {
+			vault[*f.BackupVaultArn][key] = ""
+		}
+
+		// Add metadata as tags
+		vault[*f.BackupVaultArn]["BackupVaultName"] = aws.StringValue(f.BackupVaultName)
+		vault[*f.BackupVaultArn]["Locked"] = strconv.FormatBool(aws.BoolValue(f.Locked))
+
+		// Populate the backup vault's map with the tag values
+		for k, v := range vaultTags[*f.BackupVaultArn] {
+			vault[*f.BackupVaultArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per backup vault
+	// An unlocked vault is reported as 0 since its recovery points can be deleted
+	vaultGauge := new_collector_result(reg, "aws_backup_vault_tags", "Key:Value metric per Backup vault with all tags. 1 if Locked, 0 otherwise.", "BackupVaultArn", vault)
+	for key, value := range vault {
+		if value["Locked"] == "true" {
+			vaultGauge.Set(key, 1)
+		} else {
+			vaultGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Page through the tags of a Backup plan or vault
+func list_backup_tags(svc *backup.Backup, arn *string) (map[string]*string, error) {
+	tags := make(map[string]*string)
+	err := svc.ListTagsPages(&backup.ListTagsInput{
+		ResourceArn: arn,
+	},
+		func(page *backup.ListTagsOutput, lastPage bool) bool {
+			for k, v := range page.Tags {
+				tags[k] = v
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Lists all Batch compute environment and job queue tags in us-west-2
+func get_batch_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Batch service client
+	svc := batch.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the compute environments
+	computeEnvironments := make([]*batch.ComputeEnvironmentDetail, 0)
+	err := svc.DescribeComputeEnvironmentsPages(&batch.DescribeComputeEnvironmentsInput{},
+		func(page *batch.DescribeComputeEnvironmentsOutput, lastPage bool) bool {
+			computeEnvironments = append(computeEnvironments, page.ComputeEnvironments...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the compute environments, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range computeEnvironments {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each compute environment and pupulate compute environment map
+	computeEnvironment := make(map[string]map[string]string)
+	for _, f := range computeEnvironments {
+		// Initialize the map for this compute environment
+		computeEnvironment[*f.ComputeEnvironmentArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			computeEnvironment[*f.ComputeEnvironmentArn][key] = ""
+		}
+
+		// Add metadata as tags
+		computeEnvironment[*f.ComputeEnvironmentArn]["ComputeEnvironmentName"] = aws.StringValue(f.ComputeEnvironmentName)
+		computeEnvironment[*f.ComputeEnvironmentArn]["State"] = aws.StringValue(f.State)
+		computeEnvironment[*f.ComputeEnvironmentArn]["Status"] = aws.StringValue(f.Status)
+		computeEnvironment[*f.ComputeEnvironmentArn]["Type"] = aws.StringValue(f.Type)
+
+		// Populate the compute environment's map with the tag values
+		for k, v := range f.Tags {
+			computeEnvironment[*f.ComputeEnvironmentArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per compute environment
+	computeEnvironmentGauge := new_collector_result(reg, "aws_batch_compute_environment_tags", "Key:Value metric per Batch compute environment with all tags. 1 if ENABLED and VALID, 0 otherwise.", "ComputeEnvironmentArn", computeEnvironment)
+	for key, value := range computeEnvironment {
+		if value["State"] == batch.CEStateEnabled && value["Status"] == batch.CEStatusValid {
+			computeEnvironmentGauge.Set(key, 1)
+		} else {
+			computeEnvironmentGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the job queues
+	jobQueues := make([]*batch.JobQueueDetail, 0)
+	err = svc.DescribeJobQueuesPages(&batch.DescribeJobQueuesInput{},
+		func(page *batch.DescribeJobQueuesOutput, lastPage bool) bool {
+			jobQueues = append(jobQueues, page.JobQueues...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the job queues, gather the tag names and add them to the queueTags map
+	queueTags := make(map[string]string)
+	for _, f := range jobQueues {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := queueTags[k]; !ok {
+				queueTags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each job queue and pupulate job queue map
+	jobQueue := make(map[string]map[string]string)
+	for _, f := range jobQueues {
+		// Initialize the map for this job queue
+		jobQueue[*f.JobQueueArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range queueTags {
+			jobQueue[*f.JobQueueArn][key] = ""
+		}
+
+		// Add metadata as tags
+		jobQueue[*f.JobQueueArn]["JobQueueName"] = aws.StringValue(f.JobQueueName)
+		jobQueue[*f.JobQueueArn]["State"] = aws.StringValue(f.State)
+		jobQueue[*f.JobQueueArn]["Status"] = aws.StringValue(f.Status)
+		jobQueue[*f.JobQueueArn]["Priority"] = strconv.FormatInt(aws.Int64Value(f.Priority), 10)
+
+		// Populate the job queue's map with the tag values
+		for k, v := range f.Tags {
+			jobQueue[*f.JobQueueArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per job queue
+	jobQueueGauge := new_collector_result(reg, "aws_batch_job_queue_tags", "Key:Value metric per Batch job queue with all tags. 1 if ENABLED and VALID, 0 otherwise.", "JobQueueArn", jobQueue)
+	for key, value := range jobQueue {
+		if value["State"] == batch.JQStateEnabled && value["Status"] == batch.JQStatusValid {
+			jobQueueGauge.Set(key, 1)
+		} else {
+			jobQueueGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all CloudFormation stack tags in us-west-2
+func get_cloudformation_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CloudFormation service client
+	svc := cloudformation.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the stacks, their tags are part of the response
+	stacks := make([]*cloudformation.Stack, 0)
+	err := svc.DescribeStacksPages(&cloudformation.DescribeStacksInput{},
+		func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+			stacks = append(stacks, page.Stacks...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the stacks, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range stacks {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each stack and pupulate stack map
+	stack := make(map[string]map[string]string)
+	for _, f := range stacks {
+		// Initialize the map for this stack
+		stack[*f.StackId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			stack[*f.StackId][key] = ""
+		}
+
+		// Add metadata as tags, the ParentId is empty unless the stack is nested
+		stack[*f.StackId]["StackName"] = aws.StringValue(f.StackName)
+		stack[*f.StackId]["StackStatus"] = aws.StringValue(f.StackStatus)
+		stack[*f.StackId]["ParentId"] = aws.StringValue(f.ParentId)
+
+		// Populate the stack's map with the tag values
+		for _, t := range f.Tags {
+			stack[*f.StackId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, completed stacks are 1, rolled back stacks are 0.5
+	// and failed or in progress stacks are 0
+	stackGauge := new_collector_result(reg, "aws_cloudformation_stack_tags", "Key:Value metric per CloudFormation stack with all tags, 1 if complete, 0.5 if rolled back, 0 otherwise.", "StackId", stack)
+	for _, f := range stacks {
+		switch aws.StringValue(f.StackStatus) {
+		case cloudformation.StackStatusCreateComplete, cloudformation.StackStatusUpdateComplete:
+			stackGauge.Set(*f.StackId, 1)
+		case cloudformation.StackStatusRollbackComplete, cloudformation.StackStatusUpdateRollbackComplete, cloudformation.StackStatusImportRollbackComplete:
+			stackGauge.Set(*f.StackId, 0.5)
+		default:
+			stackGauge.Set(*f.StackId, 0)
+		}
+	}
+
+	// Create and register a new gauge for the drift status of each stack
+	drift := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cloudformation_stack_drift_status",
+			Help: "1 if the CloudFormation stack has drifted from its template, 0 otherwise.",
+		},
+		[]string{"StackName", "StackId"},
+	)
+	reg.MustRegister(drift)
+	for _, f := range stacks {
+		// Stacks which were never checked for drift have no drift information
+		if f.DriftInformation != nil && aws.StringValue(f.DriftInformation.StackDriftStatus) == cloudformation.StackDriftStatusDrifted {
+			drift.WithLabelValues(aws.StringValue(f.StackName), aws.StringValue(f.StackId)).Set(1)
+		} else {
+			drift.WithLabelValues(aws.StringValue(f.StackName), aws.StringValue(f.StackId)).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all CloudFront distribution tags, CloudFront is a global service
+func get_cloudfront_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CloudFront service client, the global endpoint lives in us-east-1
+	svc := cloudfront.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// Page through all of the distributions
+	distributions := make([]*cloudfront.DistributionSummary, 0)
+	err := svc.ListDistributionsPages(&cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+			distributions = append(distributions, page.DistributionList.Items...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the distributions, gather the tag names and add them to the tags map
+	// Keep the tags for each distribution so we only list them once
+	tags := make(map[string]string)
+	distributionTags := make(map[string][]*cloudfront.Tag)
+	for _, f := range distributions {
+		// Create input for ListTagsForResource method
+		input := &cloudfront.ListTagsForResourceInput{
+			Resource: f.ARN,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		distributionTags[*f.Id] = resultTags.Tags.Items
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags.Items {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each distribution and pupulate distribution map
+	distribution := make(map[string]map[string]string)
+	for _, f := range distributions {
+		// Initialize the map for this distribution
+		distribution[*f.Id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			distribution[*f.Id][key] = ""
+		}
+
+		// Add metadata as tags
+		distribution[*f.Id]["DomainName"] = aws.StringValue(f.DomainName)
+		distribution[*f.Id]["PriceClass"] = aws.StringValue(f.PriceClass)
+		distribution[*f.Id]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the distribution's map with the tag values
+		for _, t := range distributionTags[*f.Id] {
+			distribution[*f.Id][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per distribution
+	cloudfrontTags := new_collector_result(reg, "aws_cloudfront_tags", "Key:Value metric per CloudFront distribution with all tags. 1 if Deployed, 0 if InProgress.", "DistributionId", distribution)
+	for key, value := range distribution {
+		if value["Status"] == "Deployed" {
+			cloudfrontTags.Set(key, 1)
+		} else {
+			cloudfrontTags.Set(key, 0)
+		}
+	}
+
+	// Create and register an info gauge for the HTTP version of each distribution
+	httpVersion := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cloudfront_http_version",
+			Help: "Info metric per CloudFront distribution with the maximum HTTP version served.",
+		},
+		[]string{"DistributionId", "HttpVersion"},
+	)
+	reg.MustRegister(httpVersion)
+
+	for _, f := range distributions {
+		httpVersion.WithLabelValues(aws.StringValue(f.Id), aws.StringValue(f.HttpVersion)).Set(1)
+	}
+	return nil
+}
+
+// Lists all CloudTrail trail tags and logging status in us-west-2
+func get_cloudtrail_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CloudTrail service client
+	svc := cloudtrail.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Describe all of the trails, shadow trails of other regions are included
+	result, err := svc.DescribeTrails(&cloudtrail.DescribeTrailsInput{
+		IncludeShadowTrails: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only keep the trails of this region, the tags of a trail can only be listed in its home region
+	trails := make([]*cloudtrail.Trail, 0, len(result.TrailList))
+	for _, f := range result.TrailList {
+		if aws.StringValue(f.HomeRegion) == region {
+			trails = append(trails, f)
+		}
+	}
+
+	// ListTags accepts at most 20 trail ARNs per call so the lookups are batched
+	trailTags := make(map[string][]*cloudtrail.Tag)
+	for start := 0; start < len(trails); start += 20 {
+		end := start + 20
+		if end > len(trails) {
+			end = len(trails)
+		}
+		arns := make([]*string, 0, end-start)
+		for _, f := range trails[start:end] {
+			arns = append(arns, f.TrailARN)
+		}
+		err := svc.ListTagsPages(&cloudtrail.ListTagsInput{
+			ResourceIdList: arns,
+		},
+			func(page *cloudtrail.ListTagsOutput, lastPage bool) bool {
+				for _, r := range page.ResourceTagList {
+					trailTags[aws.StringValue(r.ResourceId)] = append(trailTags[aws.StringValue(r.ResourceId)], r.TagsList...)
+				}
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate through all the trails, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range trails {
+		for _, v := range trailTags[*f.TrailARN] {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each trail and pupulate trail map
+	trail := make(map[string]map[string]string)
+	for _, f := range trails {
+		// Initialize the map for this trail
+		trail[*f.TrailARN] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			trail[*f.TrailARN][key] = ""
+		}
+
+		// Add metadata as tags
+		trail[*f.TrailARN]["Name"] = aws.StringValue(f.Name)
+		trail[*f.TrailARN]["HomeRegion"] = aws.StringValue(f.HomeRegion)
+		trail[*f.TrailARN]["IsMultiRegionTrail"] = strconv.FormatBool(aws.BoolValue(f.IsMultiRegionTrail))
+		trail[*f.TrailARN]["LogFileValidationEnabled"] = strconv.FormatBool(aws.BoolValue(f.LogFileValidationEnabled))
+
+		// Populate the trail's map with the tag values
+		for _, t := range trailTags[*f.TrailARN] {
+			trail[*f.TrailARN][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per trail
+	trailGauge := new_collector_result(reg, "aws_cloudtrail_trail_tags", "Key:Value metric per CloudTrail trail with all tags.", "TrailARN", trail)
+	for key := range trail {
+		trailGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the logging status of each trail
+	logging := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cloudtrail_trail_logging",
+			Help: "1 if the CloudTrail trail is logging, 0 otherwise.",
+		},
+		[]string{"TrailARN"},
+	)
+	reg.MustRegister(logging)
+	for _, f := range trails {
+		status, err := svc.GetTrailStatus(&cloudtrail.GetTrailStatusInput{
+			Name: f.TrailARN,
+		})
+		if err != nil {
+			return err
+		}
+		if aws.BoolValue(status.IsLogging) {
+			logging.WithLabelValues(aws.StringValue(f.TrailARN)).Set(1)
+		} else {
+			logging.WithLabelValues(aws.StringValue(f.TrailARN)).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all CloudWatch Log Group tags and retention in us-west-2
+func get_cloudwatch_log_group_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CloudWatch Logs service client
+	svc := cloudwatchlogs.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the log groups
+	logGroups := make([]*cloudwatchlogs.LogGroup, 0)
+	err := svc.DescribeLogGroupsPages(&cloudwatchlogs.DescribeLogGroupsInput{},
+		func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+			logGroups = append(logGroups, page.LogGroups...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the log groups, gather the tag names and add them to the tags map
+	// Keep the tags for each log group so we only list them once
+	tags := make(map[string]string)
+	logGroupTags := make(map[string]map[string]*string)
+	for _, f := range logGroups {
+		// Create input for ListTagsLogGroup method
+		input := &cloudwatchlogs.ListTagsLogGroupInput{
+			LogGroupName: f.LogGroupName,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsLogGroup(input)
+		if err != nil {
+			return err
+		}
+		logGroupTags[*f.LogGroupName] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each log group and pupulate log group map
+	logGroup := make(map[string]map[string]string)
+	for _, f := range logGroups {
+		// Initialize the map for this log group
+		logGroup[*f.LogGroupName] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			logGroup[*f.LogGroupName][key] = ""
+		}
+
+		// Add metadata as tags
+		logGroup[*f.LogGroupName]["Arn"] = aws.StringValue(f.Arn)
+		logGroup[*f.LogGroupName]["RetentionInDays"] = strconv.FormatInt(aws.Int64Value(f.RetentionInDays), 10)
+
+		// Populate the log group's map with the tag values
+		for k, v := range logGroupTags[*f.LogGroupName] {
+			logGroup[*f.LogGroupName][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per log group
+	logGroupGauge := new_collector_result(reg, "aws_cloudwatch_log_group_tags", "Key:Value metric per CloudWatch Log Group with all tags.", "LogGroupName", logGroup)
+	for key := range logGroup {
+		logGroupGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the retention of each log group
+	retention := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cloudwatch_log_group_retention_days",
+			Help: "Retention in days of each CloudWatch Log Group, 0 if the events never expire.",
+		},
+		[]string{"LogGroupName"},
+	)
+	reg.MustRegister(retention)
+
+	for _, f := range logGroups {
+		retention.WithLabelValues(aws.StringValue(f.LogGroupName)).Set(float64(aws.Int64Value(f.RetentionInDays)))
+	}
+	return nil
+}
+
+// Lists all CodeBuild project tags in us-west-2
+func get_codebuild_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CodeBuild service client
+	svc := codebuild.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the project names
+	names := make([]*string, 0)
+	err := svc.ListProjectsPages(&codebuild.ListProjectsInput{},
+		func(page *codebuild.ListProjectsOutput, lastPage bool) bool {
+			names = append(names, page.Projects...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// BatchGetProjects accepts at most 100 names per call so the lookups are batched
+	projects := make([]*codebuild.Project, 0, len(names))
+	for start := 0; start < len(names); start += 100 {
+		end := start + 100
+		if end > len(names) {
+			end = len(names)
+		}
+		result, err := svc.BatchGetProjects(&codebuild.BatchGetProjectsInput{
+			Names: names[start:end],
+		})
+		if err != nil {
+			return err
+		}
+		projects = append(projects, result.Projects...)
+	}
+
+	// Iterate through all the projects, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range projects {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each project and pupulate project map
+	project := make(map[string]map[string]string)
+	for _, f := range projects {
+		// Initialize the map for this project
+		project[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			project[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		project[*f.Arn]["Name"] = aws.StringValue(f.Name)
+		project[*f.Arn]["ServiceRole"] = aws.StringValue(f.ServiceRole)
+		if f.ConcurrentBuildLimit != nil {
+			project[*f.Arn]["ConcurrentBuildLimit"] = strconv.FormatInt(*f.ConcurrentBuildLimit, 10)
+		} else {
+			project[*f.Arn]["ConcurrentBuildLimit"] = ""
+		}
+
+		// Populate the project's map with the tag values
+		for _, t := range f.Tags {
+			project[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per project
+	projectGauge := new_collector_result(reg, "aws_codebuild_project_tags", "Key:Value metric per CodeBuild project with all tags.", "Arn", project)
+	for key := range project {
+		projectGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the status of the last build of each project
+	lastBuild := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_codebuild_last_build_status",
+			Help: "Status of the last build of each CodeBuild project. 1 if SUCCEEDED, 0.5 if IN_PROGRESS, 0 otherwise.",
+		},
+		[]string{"Name"},
+	)
+	reg.MustRegister(lastBuild)
+
+	for _, f := range projects {
+		// Only the newest build id is needed, the first page is sorted newest first
+		builds, err := svc.ListBuildsForProject(&codebuild.ListBuildsForProjectInput{
+			ProjectName: f.Name,
+			SortOrder:   aws.String(codebuild.SortOrderTypeDescending),
+		})
+		if err != nil {
+			return err
+		}
+		// Projects which have never been built have no status
+		if len(builds.Ids) == 0 {
+			continue
+		}
+
+		var result *codebuild.BatchGetBuildsOutput
+		result, err = svc.BatchGetBuilds(&codebuild.BatchGetBuildsInput{
+			Ids: builds.Ids[:1],
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Builds) == 0 {
+			continue
+		}
+
+		switch aws.StringValue(result.Builds[0].BuildStatus) {
+		case codebuild.StatusTypeSucceeded:
+			lastBuild.WithLabelValues(aws.StringValue(f.Name)).Set(1)
+		case codebuild.StatusTypeInProgress:
+			lastBuild.WithLabelValues(aws.StringValue(f.Name)).Set(0.5)
+		default:
+			lastBuild.WithLabelValues(aws.StringValue(f.Name)).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all CodeDeploy deployment group tags in us-west-2
+// CodeDeploy tags are set on the application, every deployment group carries the tags of its application
+func get_codedeploy_tags(sess *session.Session, region string, account awsAccount, reg prometheus.Registerer) error {
+	// Create CodeDeploy service client
+	svc := codedeploy.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the application names
+	applications := make([]*string, 0)
+	err := svc.ListApplicationsPages(&codedeploy.ListApplicationsInput{},
+		func(page *codedeploy.ListApplicationsOutput, lastPage bool) bool {
+			applications = append(applications, page.Applications...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Gather the deployment groups and the tags of every application
+	deploymentGroups := make([]*codedeploy.DeploymentGroupInfo, 0)
+	applicationTags := make(map[string][]*codedeploy.Tag)
+	for _, a := range applications {
+		// Page through the deployment group names of the application
+		names := make([]*string, 0)
+		err := svc.ListDeploymentGroupsPages(&codedeploy.ListDeploymentGroupsInput{
+			ApplicationName: a,
+		},
+			func(page *codedeploy.ListDeploymentGroupsOutput, lastPage bool) bool {
+				names = append(names, page.DeploymentGroups...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		// BatchGetDeploymentGroups accepts at most 100 names per call so the lookups are batched
+		for start := 0; start < len(names); start += 100 {
+			end := start + 100
+			if end > len(names) {
+				end = len(names)
+			}
+			result, err := svc.BatchGetDeploymentGroups(&codedeploy.BatchGetDeploymentGroupsInput{
+				ApplicationName:      a,
+				DeploymentGroupNames: names[start:end],
+			})
+			if err != nil {
+				return err
+			}
+			deploymentGroups = append(deploymentGroups, result.DeploymentGroupsInfo...)
+		}
+
+		// List out the tags of the application, its ARN is not returned by the API so it is built from the account
+		input := &codedeploy.ListTagsForResourceInput{
+			ResourceArn: aws.String(fmt.Sprintf("arn:%s:codedeploy:%s:%s:application:%s", account.partition, region, account.id, aws.StringValue(a))),
+		}
+		for {
+			resultTags, err := svc.ListTagsForResource(input)
+			if err != nil {
+				return err
+			}
+			applicationTags[*a] = append(applicationTags[*a], resultTags.Tags...)
+			if aws.StringValue(resultTags.NextToken) == "" {
+				break
+			}
+			input.NextToken = resultTags.NextToken
+		}
+	}
+
+	// Iterate through all the applications, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, applicationTag := range applicationTags {
+		for _, v := range applicationTag {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each deployment group and pupulate deployment group map
+	deploymentGroup := make(map[string]map[string]string)
+	for _, f := range deploymentGroups {
+		arn := fmt.Sprintf("arn:%s:codedeploy:%s:%s:deploymentgroup:%s/%s", account.partition, region, account.id, aws.StringValue(f.ApplicationName), aws.StringValue(f.DeploymentGroupName))
+
+		// Initialize the map for this deployment group
+		deploymentGroup[arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			deploymentGroup[arn][key] = ""
+		}
+
+		// Add metadata as tags
+		deploymentGroup[arn]["ApplicationName"] = aws.StringValue(f.ApplicationName)
+		deploymentGroup[arn]["DeploymentGroupName"] = aws.StringValue(f.DeploymentGroupName)
+		deploymentGroup[arn]["ComputePlatform"] = aws.StringValue(f.ComputePlatform)
+		deploymentGroup[arn]["DeploymentConfigName"] = aws.StringValue(f.DeploymentConfigName)
+
+		// Populate the deployment group's map with the tag values of its application
+		for _, t := range applicationTags[aws.StringValue(f.ApplicationName)] {
+			deploymentGroup[arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per deployment group
+	deploymentGroupGauge := new_collector_result(reg, "aws_codedeploy_deployment_group_tags", "Key:Value metric per CodeDeploy deployment group with all tags of its application.", "Arn", deploymentGroup)
+	for key := range deploymentGroup {
+		deploymentGroupGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all CodeStar connection tags in us-west-2
+func get_codestar_connection_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create CodeStar Connections service client
+	svc := codestarconnections.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the connections
+	connections := make([]*codestarconnections.Connection, 0)
+	err := svc.ListConnectionsPages(&codestarconnections.ListConnectionsInput{},
+		func(page *codestarconnections.ListConnectionsOutput, lastPage bool) bool {
+			connections = append(connections, page.Connections...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the connections, gather the tag names and add them to the tags map
+	// Keep the tags for each connection so we only list them once
+	tags := make(map[string]string)
+	connectionTags := make(map[string][]*codestarconnections.Tag)
+	for _, f := range connections {
+		// Create input for ListTagsForResource method
+		input := &codestarconnections.ListTagsForResourceInput{
+			ResourceArn: f.ConnectionArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		connectionTags[*f.ConnectionArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each connection and pupulate connection map
+	connection := make(map[string]map[string]string)
+	for _, f := range connections {
+		// Initialize the map for this connection
+		connection[*f.ConnectionArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			connection[*f.ConnectionArn][key] = ""
+		}
+
+		// Add metadata as tags
+		connection[*f.ConnectionArn]["ConnectionName"] = aws.StringValue(f.ConnectionName)
+		connection[*f.ConnectionArn]["ProviderType"] = aws.StringValue(f.ProviderType)
+		connection[*f.ConnectionArn]["ConnectionStatus"] = aws.StringValue(f.ConnectionStatus)
+
+		// Populate the connection's map with the tag values
+		for _, t := range connectionTags[*f.ConnectionArn] {
+			connection[*f.ConnectionArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, available connections are 1, pending and errored ones are 0
+	connectionGauge := new_collector_result(reg, "aws_codestar_connection_tags", "Key:Value metric per CodeStar connection with all tags, 1 if the connection is available.", "ConnectionArn", connection)
+	for _, f := range connections {
+		if aws.StringValue(f.ConnectionStatus) == codestarconnections.ConnectionStatusAvailable {
+			connectionGauge.Set(*f.ConnectionArn, 1)
+		} else {
+			connectionGauge.Set(*f.ConnectionArn, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Cognito User Pool tags and user counts in us-west-2
+func get_cognito_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Cognito Identity Provider service client
+	svc := cognitoidentityprovider.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the user pools
+	userPoolSummaries := make([]*cognitoidentityprovider.UserPoolDescriptionType, 0)
+	err := svc.ListUserPoolsPages(&cognitoidentityprovider.ListUserPoolsInput{MaxResults: aws.Int64(60)},
+		func(page *cognitoidentityprovider.ListUserPoolsOutput, lastPage bool) bool {
+			userPoolSummaries = append(userPoolSummaries, page.UserPools...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe each user pool, the summaries do not include tags or user counts
+	userPools := make([]*cognitoidentityprovider.UserPoolType, 0, len(userPoolSummaries))
+	for _, f := range userPoolSummaries {
+		result, err := svc.DescribeUserPool(&cognitoidentityprovider.DescribeUserPoolInput{
+			UserPoolId: f.Id,
+		})
+		if err != nil {
+			return err
+		}
+		userPools = append(userPools, result.UserPool)
+	}
+
+	// Iterate through all the user pools, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range userPools {
+		for k, _ := range f.UserPoolTags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each user pool and pupulate user pool map
+	userPool := make(map[string]map[string]string)
+	for _, f := range userPools {
+		// Initialize the map for this user pool
+		userPool[*f.Id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			userPool[*f.Id][key] = ""
+		}
+
+		// Add metadata as tags
+		userPool[*f.Id]["Name"] = aws.StringValue(f.Name)
+		userPool[*f.Id]["Status"] = aws.StringValue(f.Status)
+		userPool[*f.Id]["MfaConfiguration"] = aws.StringValue(f.MfaConfiguration)
+
+		// Populate the user pool's map with the tag values
+		for k, v := range f.UserPoolTags {
+			userPool[*f.Id][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per user pool
+	userPoolGauge := new_collector_result(reg, "aws_cognito_userpool_tags", "Key:Value metric per Cognito User Pool with all tags. 1 if Enabled, 0 otherwise.", "UserPoolId", userPool)
+	for key, value := range userPool {
+		if value["Status"] == cognitoidentityprovider.StatusTypeEnabled {
+			userPoolGauge.Set(key, 1)
+		} else {
+			userPoolGauge.Set(key, 0)
+		}
+	}
+
+	// Create and register a new gauge for the estimated number of users in each user pool
+	userCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cognito_user_count",
+			Help: "Estimated number of users in each Cognito User Pool.",
+		},
+		[]string{"UserPoolId", "Name"},
+	)
+	reg.MustRegister(userCount)
+
+	for _, f := range userPools {
+		userCount.WithLabelValues(aws.StringValue(f.Id), aws.StringValue(f.Name)).Set(float64(aws.Int64Value(f.EstimatedNumberOfUsers)))
+	}
+	return nil
+}
+
+// Lists all Comprehend document classifier and entity recognizer tags in us-west-2
+func get_comprehend_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Comprehend service client
+	svc := comprehend.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the document classifiers
+	classifiers := make([]*comprehend.DocumentClassifierProperties, 0)
+	err := svc.ListDocumentClassifiersPages(&comprehend.ListDocumentClassifiersInput{},
+		func(page *comprehend.ListDocumentClassifiersOutput, lastPage bool) bool {
+			classifiers = append(classifiers, page.DocumentClassifierPropertiesList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the document classifiers, gather the tag names and add them to the tags map
+	// Keep the tags for each document classifier so we only list them once
+	tags := make(map[string]string)
+	classifierTags := make(map[string][]*comprehend.Tag)
+	for _, f := range classifiers {
+		// Create input for ListTagsForResource method
+		input := &comprehend.ListTagsForResourceInput{
+			ResourceArn: f.DocumentClassifierArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		classifierTags[*f.DocumentClassifierArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each document classifier and pupulate document classifier map
+	classifier := make(map[string]map[string]string)
+	for _, f := range classifiers {
+		// Initialize the map for this document classifier
+		classifier[*f.DocumentClassifierArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			classifier[*f.DocumentClassifierArn][key] = ""
+		}
+
+		// Add metadata as tags
+		classifier[*f.DocumentClassifierArn]["LanguageCode"] = aws.StringValue(f.LanguageCode)
+		classifier[*f.DocumentClassifierArn]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the document classifier's map with the tag values
+		for _, t := range classifierTags[*f.DocumentClassifierArn] {
+			classifier[*f.DocumentClassifierArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per document classifier
+	classifierGauge := new_collector_result(reg, "aws_comprehend_classifier_tags", "Key:Value metric per Comprehend document classifier with all tags. 1 if TRAINED, 0 otherwise.", "DocumentClassifierArn", classifier)
+	for key, value := range classifier {
+		if value["Status"] == comprehend.ModelStatusTrained {
+			classifierGauge.Set(key, 1)
+		} else {
+			classifierGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the entity recognizers
+	recognizers := make([]*comprehend.EntityRecognizerProperties, 0)
+	err = svc.ListEntityRecognizersPages(&comprehend.ListEntityRecognizersInput{},
+		func(page *comprehend.ListEntityRecognizersOutput, lastPage bool) bool {
+			recognizers = append(recognizers, page.EntityRecognizerPropertiesList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the entity recognizers, gather the tag names and add them to the recognizerTagNames map
+	// Keep the tags for each entity recognizer so we only list them once
+	recognizerTagNames := make(map[string]string)
+	recognizerTags := make(map[string][]*comprehend.Tag)
+	for _, f := range recognizers {
+		// Create input for ListTagsForResource method
+		input := &comprehend.ListTagsForResourceInput{
+			ResourceArn: f.EntityRecognizerArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		recognizerTags[*f.EntityRecognizerArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := recognizerTagNames[*v.Key]; !ok {
+				recognizerTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each entity recognizer and pupulate entity recognizer map
+	recognizer := make(map[string]map[string]string)
+	for _, f := range recognizers {
+		// Initialize the map for this entity recognizer
+		recognizer[*f.EntityRecognizerArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range recognizerTagNames {
+			recognizer[*f.EntityRecognizerArn][key] = ""
+		}
+
+		// Add metadata as tags
+		recognizer[*f.EntityRecognizerArn]["LanguageCode"] = aws.StringValue(f.LanguageCode)
+		recognizer[*f.EntityRecognizerArn]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the entity recognizer's map with the tag values
+		for _, t := range recognizerTags[*f.EntityRecognizerArn] {
+			recognizer[*f.EntityRecognizerArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per entity recognizer
+	recognizerGauge := new_collector_result(reg, "aws_comprehend_recognizer_tags", "Key:Value metric per Comprehend entity recognizer with all tags. 1 if TRAINED, 0 otherwise.", "EntityRecognizerArn", recognizer)
+	for key, value := range recognizer {
+		if value["Status"] == comprehend.ModelStatusTrained {
+			recognizerGauge.Set(key, 1)
+		} else {
+			recognizerGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Connect instance tags in us-west-2
+func get_connect_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Connect service client
+	svc := connect.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the instances
+	instances := make([]*connect.InstanceSummary, 0)
+	err := svc.ListInstancesPages(&connect.ListInstancesInput{},
+		func(page *connect.ListInstancesOutput, lastPage bool) bool {
+			instances = append(instances, page.InstanceSummaryList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the instances, gather the tag names and add them to the tags map
+	// Keep the tags for each instance so we only list them once
+	tags := make(map[string]string)
+	instanceTags := make(map[string]map[string]*string)
+	for _, f := range instances {
+		// Create input for ListTagsForResource method
+		input := &connect.ListTagsForResourceInput{
+			ResourceArn: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		instanceTags[*f.Arn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each instance and pupulate instance map
+	instance := make(map[string]map[string]string)
+	for _, f := range instances {
+		// Initialize the map for this instance
+		instance[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			instance[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		instance[*f.Arn]["Id"] = aws.StringValue(f.Id)
+		instance[*f.Arn]["InstanceAlias"] = aws.StringValue(f.InstanceAlias)
+		instance[*f.Arn]["InstanceStatus"] = aws.StringValue(f.InstanceStatus)
+		instance[*f.Arn]["ServiceRole"] = aws.StringValue(f.ServiceRole)
+
+		// Populate the instance's map with the tag values
+		for k, v := range instanceTags[*f.Arn] {
+			instance[*f.Arn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per instance
+	instanceGauge := new_collector_result(reg, "aws_connect_instance_tags", "Key:Value metric per Connect instance with all tags. 1 if ACTIVE, 0 otherwise.", "Arn", instance)
+	for key, value := range instance {
+		if value["InstanceStatus"] == connect.InstanceStatusActive {
+			instanceGauge.Set(key, 1)
+		} else {
+			instanceGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Cost Explorer has low rate limits and charges per request, so the costs are cached between runs
+const costCacheTTL = time.Hour
+
+// Costs per service of one account as cached on disk
+type costCache struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Costs     map[string]float64 `json:"costs"`
+}
+
+// Report the blended cost of the current month per service
+func get_cost_metrics(sess *session.Session, region string, account awsAccount, reg prometheus.Registerer) error {
+	// Discovered accounts collect in parallel, so every account gets its own cache file
+	// The month is part of the name, so costs cached late in a month are never reported for the next one
+	month := time.Now().UTC().Format("2006-01")
+	cacheDir, err := cost_cache_dir()
+	if err != nil {
+		log.Printf("WARNING: Could not create the cost cache directory, costs are not cached: %v", err)
+	}
+	cacheFile := ""
+	if cacheDir != "" {
+		cacheFile = filepath.Join(cacheDir, "cost-"+account.id+"-"+month+".json")
+	}
+
+	// Only ask Cost Explorer when the cached costs are missing or older than the TTL
+	var cache costCache
+	if !read_cost_cache(cacheFile, &cache) || time.Since(cache.Timestamp) > costCacheTTL {
+		costs, err := get_cost_and_usage(sess)
+		if err != nil {
+			return err
+		}
+		cache = costCache{Timestamp: time.Now(), Costs: costs}
+		if data, err := json.Marshal(cache); err == nil && cacheFile != "" {
+			if err := write_cost_cache(cacheFile, data); err != nil {
+				log.Printf("WARNING: Could not write the cost cache '%s': %v", cacheFile, err)
+			}
+		}
+	}
+
+	// Create and register a new gauge for the cost of each service
+	cost := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_cost_usage_usd",
+			Help: "Blended cost of the current month per service in USD. WARNING: Cost Explorer is eventually consistent and lags behind by up to a day, cached for an hour.",
+		},
+		[]string{"ServiceName"},
+	)
+	reg.MustRegister(cost)
+	for service, amount := range cache.Costs {
+		cost.WithLabelValues(service).Set(amount)
+	}
+	return nil
+}
+
+// Private directory for the cost cache in the user's cache directory, so other users can't read or replace it
+func cost_cache_dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "nubis-prometheus-exposition")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Read the cost cache, false if there is no cache file or it can't be read
+func read_cost_cache(cacheFile string, cache *costCache) bool {
+	if cacheFile == "" {
+		return false
+	}
+	data, err := ioutil.ReadFile(cacheFile)
+	return err == nil && json.Unmarshal(data, cache) == nil
+}
+
+// Write the cost cache through an exclusively created temp file like write_file, a failed write leaves the old cache
+func write_cost_cache(cacheFile string, data []byte) error {
+	s1 := rand.NewSource(time.Now().UnixNano())
+	r1 := rand.New(s1)
+	tmpName := fmt.Sprintf("%s.tmp%d", cacheFile, r1.Intn(10000))
+
+	tmpFile, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpName)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, cacheFile)
+}
+
+// Query the blended cost of the current month per service
+func get_cost_and_usage(sess *session.Session) (map[string]float64, error) {
+	// Create Cost Explorer service client, the global endpoint lives in us-east-1
+	svc := costexplorer.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// The end date is exclusive, ending tomorrow includes today and keeps the period valid on the first of the month
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	input := &costexplorer.GetCostAndUsageInput{
+		Granularity: aws.String(costexplorer.GranularityMonthly),
+		Metrics:     aws.StringSlice([]string{"BlendedCost"}),
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionService),
+			},
+		},
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(now.AddDate(0, 0, 1).Format("2006-01-02")),
+		},
+	}
+
+	// Page through all of the results and add up the cost of each service
+	costs := make(map[string]float64)
+	for {
+		result, err := svc.GetCostAndUsage(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.ResultsByTime {
+			for _, g := range r.Groups {
+				metric, ok := g.Metrics["BlendedCost"]
+				if !ok || len(g.Keys) == 0 {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.StringValue(metric.Amount), 64)
+				if err != nil {
+					return nil, err
+				}
+				costs[aws.StringValue(g.Keys[0])] += amount
+			}
+		}
+		if aws.StringValue(result.NextPageToken) == "" {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+	return costs, nil
+}
+
+// Lists all DataSync task tags in us-west-2
+func get_datasync_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create DataSync service client
+	svc := datasync.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the tasks
+	taskList := make([]*datasync.TaskListEntry, 0)
+	err := svc.ListTasksPages(&datasync.ListTasksInput{},
+		func(page *datasync.ListTasksOutput, lastPage bool) bool {
+			taskList = append(taskList, page.Tasks...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the tasks, describe them, gather the tag names and add them to the tags map
+	// Keep the tags for each task so we only list them once
+	tags := make(map[string]string)
+	taskTags := make(map[string][]*datasync.TagListEntry)
+	tasks := make([]*datasync.DescribeTaskOutput, 0, len(taskList))
+	for _, f := range taskList {
+		// Describe the task for its status and locations
+		result, err := svc.DescribeTask(&datasync.DescribeTaskInput{
+			TaskArn: f.TaskArn,
+		})
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, result)
+
+		// Page through the tags of the task
+		resultTags := make([]*datasync.TagListEntry, 0)
+		err = svc.ListTagsForResourcePages(&datasync.ListTagsForResourceInput{
+			ResourceArn: f.TaskArn,
+		},
+			func(page *datasync.ListTagsForResourceOutput, lastPage bool) bool {
+				resultTags = append(resultTags, page.Tags...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+		taskTags[*f.TaskArn] = resultTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each task and pupulate task map
+	task := make(map[string]map[string]string)
+	for _, f := range tasks {
+		// Initialize the map for this task
+		task[*f.TaskArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			task[*f.TaskArn][key] = ""
+		}
+
+		// Add metadata as tags
+		task[*f.TaskArn]["Name"] = aws.StringValue(f.Name)
+		task[*f.TaskArn]["Status"] = aws.StringValue(f.Status)
+		task[*f.TaskArn]["SourceLocationArn"] = aws.StringValue(f.SourceLocationArn)
+		task[*f.TaskArn]["DestinationLocationArn"] = aws.StringValue(f.DestinationLocationArn)
+
+		// Populate the task's map with the tag values
+		for _, t := range taskTags[*f.TaskArn] {
+			task[*f.TaskArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per task
+	taskGauge := new_collector_result(reg, "aws_datasync_task_tags", "Key:Value metric per DataSync task with all tags. 1 if AVAILABLE, 0 otherwise.", "TaskArn", task)
+	for key, value := range task {
+		if value["Status"] == datasync.TaskStatusAvailable {
+			taskGauge.Set(key, 1)
+		} else {
+			taskGauge.Set(key, 0)
+		}
+	}
+
+	// Create and register a new gauge for the tasks currently executing
+	running := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_datasync_task_running",
+			Help: "1 if the DataSync task is currently executing, 0 otherwise.",
+		},
+		[]string{"TaskArn"},
+	)
+	reg.MustRegister(running)
+	for _, f := range tasks {
+		if aws.StringValue(f.Status) == datasync.TaskStatusRunning {
+			running.WithLabelValues(aws.StringValue(f.TaskArn)).Set(1)
+		} else {
+			running.WithLabelValues(aws.StringValue(f.TaskArn)).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all Direct Connect connection and virtual interface tags in us-west-2
+func get_directconnect_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Direct Connect service client
+	svc := directconnect.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Tags are described by ARN, which is built by hand in the partition of the region
+	partition := partition_for_region(region)
+
+	result, err := svc.DescribeConnections(nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the ARN of every connection, DescribeTags only accepts ARNs
+	connectionArns := make(map[string]string)
+	for _, f := range result.Connections {
+		connectionArns[*f.ConnectionId] = fmt.Sprintf("arn:%s:directconnect:%s:%s:dxcon/%s", partition, region, aws.StringValue(f.OwnerAccount), *f.ConnectionId)
+	}
+	connectionTags, err := describe_directconnect_tags(svc, connectionArns)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the connections, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, t := range connectionTags {
+		for _, v := range t {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each connection and pupulate connection map
+	connection := make(map[string]map[string]string)
+	for _, f := range result.Connections {
+		// Initialize the map for this connection
+		connection[*f.ConnectionId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			connection[*f.ConnectionId][key] = ""
+		}
+
+		// Add metadata as tags
+		connection[*f.ConnectionId]["ConnectionName"] = aws.StringValue(f.ConnectionName)
+		connection[*f.ConnectionId]["ConnectionState"] = aws.StringValue(f.ConnectionState)
+		connection[*f.ConnectionId]["Bandwidth"] = aws.StringValue(f.Bandwidth)
+		connection[*f.ConnectionId]["Location"] = aws.StringValue(f.Location)
+
+		// Populate the connection's map with the tag values
+		for _, t := range connectionTags[*f.ConnectionId] {
+			connection[*f.ConnectionId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per connection
+	connectionGauge := new_collector_result(reg, "aws_directconnect_connection_tags", "Key:Value metric per Direct Connect connection with all tags. 1 if available, 0 otherwise.", "ConnectionId", connection)
+	for key, value := range connection {
+		if value["ConnectionState"] == directconnect.ConnectionStateAvailable {
+			connectionGauge.Set(key, 1)
+		} else {
+			connectionGauge.Set(key, 0)
+		}
+	}
+
+	var resultVirtualInterfaces *directconnect.DescribeVirtualInterfacesOutput
+	resultVirtualInterfaces, err = svc.DescribeVirtualInterfaces(nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the ARN of every virtual interface and look up their tags
+	virtualInterfaceArns := make(map[string]string)
+	for _, f := range resultVirtualInterfaces.VirtualInterfaces {
+		virtualInterfaceArns[*f.VirtualInterfaceId] = fmt.Sprintf("arn:%s:directconnect:%s:%s:dxvif/%s", partition, region, aws.StringValue(f.OwnerAccount), *f.VirtualInterfaceId)
+	}
+	virtualInterfaceTags, err := describe_directconnect_tags(svc, virtualInterfaceArns)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the virtual interfaces, gather the tag names and add them to the tags map
+	vifTags := make(map[string]string)
+	for _, t := range virtualInterfaceTags {
+		for _, v := range t {
+			// If the key is not in the map, add it
+			if _, ok := vifTags[*v.Key]; !ok {
+				vifTags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each virtual interface and pupulate virtual interface map
+	virtualInterface := make(map[string]map[string]string)
+	for _, f := range resultVirtualInterfaces.VirtualInterfaces {
+		// Initialize the map for this virtual interface
+		virtualInterface[*f.VirtualInterfaceId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range vifTags {
+			virtualInterface[*f.VirtualInterfaceId][key] = ""
+		}
+
+		// Add metadata as tags
+		virtualInterface[*f.VirtualInterfaceId]["VirtualInterfaceName"] = aws.StringValue(f.VirtualInterfaceName)
+		virtualInterface[*f.VirtualInterfaceId]["VirtualInterfaceType"] = aws.StringValue(f.VirtualInterfaceType)
+		virtualInterface[*f.VirtualInterfaceId]["VirtualInterfaceState"] = aws.StringValue(f.VirtualInterfaceState)
+		virtualInterface[*f.VirtualInterfaceId]["VlanId"] = strconv.FormatInt(aws.Int64Value(f.Vlan), 10)
+
+		// Populate the virtual interface's map with the tag values
+		for _, t := range virtualInterfaceTags[*f.VirtualInterfaceId] {
+			virtualInterface[*f.VirtualInterfaceId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per virtual interface
+	virtualInterfaceGauge := new_collector_result(reg, "aws_directconnect_virtual_interface_tags", "Key:Value metric per Direct Connect virtual interface with all tags. 1 if available, 0 otherwise.", "VirtualInterfaceId", virtualInterface)
+	for key, value := range virtualInterface {
+		if value["VirtualInterfaceState"] == directconnect.VirtualInterfaceStateAvailable {
+			virtualInterfaceGauge.Set(key, 1)
+		} else {
+			virtualInterfaceGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Look up the tags of Direct Connect resources given a map of resource id to ARN
+// DescribeTags accepts at most 20 ARNs per call so the lookups are batched
+func describe_directconnect_tags(svc *directconnect.DirectConnect, arns map[string]string) (map[string][]*directconnect.Tag, error) {
+	// Map the ARNs back to their resource id
+	ids := make(map[string]string)
+	batch := make([]*string, 0, 20)
+	batches := make([][]*string, 0)
+	for id, arn := range arns {
+		ids[arn] = id
+		batch = append(batch, aws.String(arn))
+		if len(batch) == 20 {
+			batches = append(batches, batch)
+			batch = make([]*string, 0, 20)
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	resourceTags := make(map[string][]*directconnect.Tag)
+	for _, b := range batches {
+		result, err := svc.DescribeTags(&directconnect.DescribeTagsInput{
+			ResourceArns: b,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.ResourceTags {
+			resourceTags[ids[aws.StringValue(r.ResourceArn)]] = r.Tags
+		}
+	}
+	return resourceTags, nil
+}
+
+// Lists all DocumentDB cluster tags in us-west-2
+// DocumentDB is served by the RDS API so the RDS client is shared with get_rds_tags
+func get_documentdb_tags(svc *rds.RDS, reg prometheus.Registerer) error {
+	// Page through all of the clusters running the docdb engine
+	clusters := make([]*rds.DBCluster, 0)
+	input := &rds.DescribeDBClustersInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("engine"),
+				Values: []*string{aws.String("docdb")},
+			},
+		},
+	}
+	err := svc.DescribeDBClustersPages(input,
+		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.DBClusters...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the clusters, gather the tag names and add them to the tags map
+	// Keep the tags for each cluster so we only list them once
+	tags := make(map[string]string)
+	clusterTags := make(map[string][]*rds.Tag)
+	for _, f := range clusters {
+		// Create input for ListTagsForResource method
+		input := &rds.ListTagsForResourceInput{
+			ResourceName: f.DBClusterArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		clusterTags[*f.DBClusterArn] = resultTags.TagList
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.TagList {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each cluster and pupulate cluster map
+	cluster := make(map[string]map[string]string)
+	for _, f := range clusters {
+		// Initialize the map for this cluster
+		cluster[*f.DBClusterArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			cluster[*f.DBClusterArn][key] = ""
+		}
+
+		// Add metadata as tags
+		cluster[*f.DBClusterArn]["DBClusterIdentifier"] = aws.StringValue(f.DBClusterIdentifier)
+		cluster[*f.DBClusterArn]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the cluster's map with the tag values
+		for _, t := range clusterTags[*f.DBClusterArn] {
+			cluster[*f.DBClusterArn][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per cluster
+	documentdb := new_collector_result(reg, "aws_documentdb_cluster_tags", "Key:Value metric per DocumentDB cluster with all tags.", "DBClusterArn", cluster)
+	for key := range cluster {
+		documentdb.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all tags for all instances in us-west-2
+// Iterate through instances to ONLY look up keys and add unique to map
+// Create new guage with keys from map
+// Iterate through instances making one guage metric each with all key:value pairs populated
+func get_ec2_instance_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Only describe the instances matching --ec2-filter
+	filters := make([]*ec2.Filter, 0, len(ec2Filters))
+	for _, f := range ec2Filters {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String(f.Name),
+			Values: aws.StringSlice(f.Values),
+		})
+	}
+
+	result, err := svc.DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the instances, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.Reservations {
+		if len(f.Instances) > 0 {
+			for _, v := range f.Instances {
+				if len(v.Tags) > 0 {
+					for _, v := range v.Tags {
+						// If the key is not in the map, add it
+						if _, ok := tags[*v.Key]; !ok {
+							tags[*v.Key] = ""
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Gather all tags for each instance and pupulate instance map
+	instances := make(map[string]map[string]string)
+	// Iterate through all the instances and create one entry with all tags
+	for _, f := range result.Reservations {
+		if len(f.Instances) > 0 {
+			for _, i := range f.Instances {
+				// Initialize the map for this instance
+				instances[*i.InstanceId] = make(map[string]string)
+
+				// Add all keys to the map. It is necessary to have every tag for the metric
+				for key, _ := range tags {
+					instances[*i.InstanceId][key] = ""
+				}
+
+				// Populate the instance's map with the tag values
+				if len(i.Tags) > 0 {
+					for _, t := range i.Tags {
+						instances[*i.InstanceId][*t.Key] = *t.Value
+					}
+				}
+			}
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per instance
+	ec2 := new_collector_result(reg, "aws_ec2_tags", "Key:Value metric per EC2 instances with all tags.", "InstanceId", instances)
+	for key := range instances {
+		ec2.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the launch time of each instance, time() minus it is the instance age
+	launchTime := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ec2_instance_launch_timestamp_seconds",
+			Help: "Unix timestamp at which the EC2 instance was launched.",
+		},
+		[]string{"InstanceId", "InstanceType"},
+	)
+	reg.MustRegister(launchTime)
+	for _, f := range result.Reservations {
+		for _, i := range f.Instances {
+			if i.LaunchTime != nil {
+				launchTime.WithLabelValues(aws.StringValue(i.InstanceId), aws.StringValue(i.InstanceType)).Set(float64(i.LaunchTime.Unix()))
+			}
+		}
+	}
+
+	// Reuse the described instances for the CPU credit balance of the burstable ones
+	return get_ec2_cpu_credit_metrics(sess, region, result.Reservations, reg)
+}
+
+// Report the latest CPU credit balance of the burstable instances from CloudWatch
+func get_ec2_cpu_credit_metrics(sess *session.Session, region string, reservations []*ec2.Reservation, reg prometheus.Registerer) error {
+	// Create CloudWatch service client
+	svc := cloudwatch.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Only the burstable instance families earn CPU credits
+	burstable := make([]*ec2.Instance, 0)
+	for _, f := range reservations {
+		for _, i := range f.Instances {
+			for _, prefix := range []string{"t2.", "t3.", "t3a.", "t4g."} {
+				if strings.HasPrefix(aws.StringValue(i.InstanceType), prefix) {
+					burstable = append(burstable, i)
+				}
+			}
+		}
+	}
+
+	// Create and register a new gauge for the credit balance of each instance
+	balance := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ec2_cpu_credit_balance",
+			Help: "Latest CPU credit balance of the burstable EC2 instance.",
+		},
+		[]string{"InstanceId", "InstanceType"},
+	)
+	reg.MustRegister(balance)
+
+	// GetMetricData accepts at most 500 queries per call so the lookups are batched,
+	// one GetMetricStatistics call per instance would quickly hit the CloudWatch rate limits
+	end := time.Now()
+	for start := 0; start < len(burstable); start += 500 {
+		stop := start + 500
+		if stop > len(burstable) {
+			stop = len(burstable)
+		}
+
+		// Query ids must start with a lowercase letter, they map the results back to the instances
+		queries := make([]*cloudwatch.MetricDataQuery, 0, stop-start)
+		batch := make(map[string]*ec2.Instance)
+		for n, i := range burstable[start:stop] {
+			id := fmt.Sprintf("m%d", n)
+			batch[id] = i
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(id),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String("AWS/EC2"),
+						MetricName: aws.String("CPUCreditBalance"),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String("InstanceId"),
+								Value: i.InstanceId,
+							},
+						},
+					},
+					Period: aws.Int64(300),
+					Stat:   aws.String(cloudwatch.StatisticAverage),
+				},
+			})
+		}
+
+		// The credit balance is published every 5 minutes, the newest datapoint comes first
+		err := svc.GetMetricDataPages(&cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			StartTime:         aws.Time(end.Add(-30 * time.Minute)),
+			EndTime:           aws.Time(end),
+			ScanBy:            aws.String(cloudwatch.ScanByTimestampDescending),
+		},
+			func(page *cloudwatch.GetMetricDataOutput, lastPage bool) bool {
+				for _, r := range page.MetricDataResults {
+					i, ok := batch[aws.StringValue(r.Id)]
+					if !ok || len(r.Values) == 0 {
+						continue
+					}
+					balance.WithLabelValues(aws.StringValue(i.InstanceId), aws.StringValue(i.InstanceType)).Set(aws.Float64Value(r.Values[0]))
+				}
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lists all ECR repository tags and image counts in us-west-2
+func get_ecr_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create ECR service client
+	svc := ecr.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the repositories
+	repositories := make([]*ecr.Repository, 0)
+	err := svc.DescribeRepositoriesPages(&ecr.DescribeRepositoriesInput{},
+		func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+			repositories = append(repositories, page.Repositories...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the repositories, gather the tag names and add them to the tags map
+	// Keep the tags for each repository so we only list them once
+	tags := make(map[string]string)
+	repositoryTags := make(map[string][]*ecr.Tag)
+	for _, f := range repositories {
+		// Create input for ListTagsForResource method
+		input := &ecr.ListTagsForResourceInput{
+			ResourceArn: f.RepositoryArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		repositoryTags[*f.RepositoryArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each repository and pupulate repository map
+	repository := make(map[string]map[string]string)
+	for _, f := range repositories {
+		// Initialize the map for this repository
+		repository[*f.RepositoryArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			repository[*f.RepositoryArn][key] = ""
+		}
+
+		// Add metadata as tags
+		repository[*f.RepositoryArn]["RepositoryName"] = aws.StringValue(f.RepositoryName)
+		repository[*f.RepositoryArn]["RegistryId"] = aws.StringValue(f.RegistryId)
+
+		// Populate the repository's map with the tag values
+		for _, t := range repositoryTags[*f.RepositoryArn] {
+			repository[*f.RepositoryArn][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per repository
+	ecrTags := new_collector_result(reg, "aws_ecr_repository_tags", "Key:Value metric per ECR repository with all tags.", "RepositoryArn", repository)
+	for key := range repository {
+		ecrTags.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the image count of each repository
+	imageCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ecr_image_count",
+			Help: "Number of images stored in each ECR repository.",
+		},
+		[]string{"RepositoryName", "RepositoryArn", "RegistryId"},
+	)
+	reg.MustRegister(imageCount)
+
+	// Page through the images of each repository and count them
+	// Keep the latest image of each repository for its scan findings
+	latestImages := make(map[string]*ecr.ImageDetail)
+	for _, f := range repositories {
+		input := &ecr.DescribeImagesInput{
+			RegistryId:     f.RegistryId,
+			RepositoryName: f.RepositoryName,
+		}
+		count := 0
+		err := svc.DescribeImagesPages(input,
+			func(page *ecr.DescribeImagesOutput, lastPage bool) bool {
+				count += len(page.ImageDetails)
+				for _, i := range page.ImageDetails {
+					latestImages[*f.RepositoryArn] = latest_ecr_image(latestImages[*f.RepositoryArn], i)
+				}
+				return true
+			})
+		if err != nil {
+			return err
+		}
+		imageCount.WithLabelValues(aws.StringValue(f.RepositoryName), aws.StringValue(f.RepositoryArn), aws.StringValue(f.RegistryId)).Set(float64(count))
+	}
+	return get_ecr_scan_findings(svc, repositories, latestImages, reg)
+}
+
+// Check whether an image carries the latest tag
+func ecr_image_tagged_latest(image *ecr.ImageDetail) bool {
+	for _, t := range image.ImageTags {
+		if aws.StringValue(t) == "latest" {
+			return true
+		}
+	}
+	return false
+}
+
+// Pick the latest of two images, an image tagged latest wins over the most recently pushed one
+func latest_ecr_image(current *ecr.ImageDetail, image *ecr.ImageDetail) *ecr.ImageDetail {
+	if current == nil || ecr_image_tagged_latest(image) {
+		return image
+	}
+	if !ecr_image_tagged_latest(current) && aws.TimeValue(image.ImagePushedAt).After(aws.TimeValue(current.ImagePushedAt)) {
+		return image
+	}
+	return current
+}
+
+// Count the scan findings of the latest image of each repository per severity
+func get_ecr_scan_findings(svc *ecr.ECR, repositories []*ecr.Repository, latestImages map[string]*ecr.ImageDetail, reg prometheus.Registerer) error {
+	// Create and register a new gauge for the findings of each image per severity
+	findings := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ecr_image_scan_findings",
+			Help: "Number of scan findings of the latest image of each ECR repository per severity.",
+		},
+		[]string{"RepositoryName", "ImageTag", "Severity"},
+	)
+	reg.MustRegister(findings)
+
+	for _, f := range repositories {
+		image, ok := latestImages[*f.RepositoryArn]
+		if !ok {
+			continue
+		}
+
+		// Untagged images have an empty ImageTag, the digest still identifies them for the scan
+		imageTag := ""
+		if ecr_image_tagged_latest(image) {
+			imageTag = "latest"
+		} else if len(image.ImageTags) > 0 {
+			imageTag = aws.StringValue(image.ImageTags[0])
+		}
+
+		result, err := svc.DescribeImageScanFindings(&ecr.DescribeImageScanFindingsInput{
+			RegistryId:     f.RegistryId,
+			RepositoryName: f.RepositoryName,
+			ImageId: &ecr.ImageIdentifier{
+				ImageDigest: image.ImageDigest,
+			},
+		})
+		if err != nil {
+			// Images which were never scanned have no findings
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ecr.ErrCodeScanNotFoundException {
+				continue
+			}
+			return err
+		}
+		if result.ImageScanFindings == nil {
+			continue
+		}
+
+		// Every severity is set so a fixed vulnerability drops back to 0
+		for _, severity := range ecr.FindingSeverity_Values() {
+			count := aws.Int64Value(result.ImageScanFindings.FindingSeverityCounts[severity])
+			findings.WithLabelValues(aws.StringValue(f.RepositoryName), imageTag, severity).Set(float64(count))
+		}
+	}
+	return nil
+}
+
+// Lists all EFS tags in us-west-2
+func get_efs_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EFS service client
+	svc := efs.New(sess, &aws.Config{Region: aws.String(region)})
+
+	result, err := svc.DescribeFileSystems(nil)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the filesystems, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.FileSystems {
+		// Create input for DescribeTags method
+		fileSystemId := aws.StringValue(f.FileSystemId)
+		input := &efs.DescribeTagsInput{
+			FileSystemId: aws.String(fileSystemId),
+		}
+
+		// List out the tags
+		resultTags, err := svc.DescribeTags(input)
+		if err != nil {
+			return err
+		}
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each fileSystem and pupulate fileSystem map
+	fileSystem := make(map[string]map[string]string)
+	// Iterate through all the fileSystem and create one entry with all tags
+	for _, f := range result.FileSystems {
+		// Create input for DescribeTags method
+		fileSystemId := aws.StringValue(f.FileSystemId)
+		input := &efs.DescribeTagsInput{
+			FileSystemId: aws.String(fileSystemId),
+		}
+
+		// List out the tags
+		resultTags, err := svc.DescribeTags(input)
+		if err != nil {
+			return err
+		}
+
+		// Initialize the map for this FileSystem
+		fileSystem[*f.FileSystemId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			fileSystem[*f.FileSystemId][key] = ""
+		}
+
+		// Populate the fileSystem's map with the tag values
+		for _, t := range resultTags.Tags {
+			fileSystem[*f.FileSystemId][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per filesystem
+	efs := new_collector_result(reg, "aws_efs_tags", "Key:Value metric per EFS fileSystem with all tags.", "FileSystemId", fileSystem)
+	for key := range fileSystem {
+		efs.Set(key, 1)
+	}
+
+	// Access points and mount targets are collected with the same client
+	if err := get_efs_access_point_tags(svc, reg); err != nil {
+		return err
+	}
+	return get_efs_mount_target_metrics(svc, result.FileSystems, reg)
+}
+
+// Lists all EFS access point tags with the client of the EFS collector
+func get_efs_access_point_tags(svc *efs.EFS, reg prometheus.Registerer) error {
+	// Page through all of the access points, their tags are part of the response
+	accessPoints := make([]*efs.AccessPointDescription, 0)
+	err := svc.DescribeAccessPointsPages(&efs.DescribeAccessPointsInput{},
+		func(page *efs.DescribeAccessPointsOutput, lastPage bool) bool {
+			accessPoints = append(accessPoints, page.AccessPoints...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the access points, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range accessPoints {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each access point and pupulate accessPoint map
+	accessPoint := make(map[string]map[string]string)
+	for _, f := range accessPoints {
+		// Initialize the map for this access point
+		accessPoint[*f.AccessPointId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			accessPoint[*f.AccessPointId][key] = ""
+		}
+
+		// Add metadata as tags
+		accessPoint[*f.AccessPointId]["AccessPointArn"] = aws.StringValue(f.AccessPointArn)
+		accessPoint[*f.AccessPointId]["FileSystemId"] = aws.StringValue(f.FileSystemId)
+		accessPoint[*f.AccessPointId]["LifeCycleState"] = aws.StringValue(f.LifeCycleState)
+
+		// Populate the access point's map with the tag values
+		for _, t := range f.Tags {
+			accessPoint[*f.AccessPointId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, available access points are 1 and all others are 0
+	accessPointGauge := new_collector_result(reg, "aws_efs_access_point_tags", "Key:Value metric per EFS access point with all tags, 1 if the access point is available.", "AccessPointId", accessPoint)
+	for _, f := range accessPoints {
+		if aws.StringValue(f.LifeCycleState) == efs.LifeCycleStateAvailable {
+			accessPointGauge.Set(*f.AccessPointId, 1)
+		} else {
+			accessPointGauge.Set(*f.AccessPointId, 0)
+		}
+	}
+	return nil
+}
+
+// Report the state of the mount targets of every EFS file system, one per availability zone
+func get_efs_mount_target_metrics(svc *efs.EFS, fileSystems []*efs.FileSystemDescription, reg prometheus.Registerer) error {
+	// Create and register a new gauge for the state of each mount target
+	mountTarget := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_efs_mount_target_state",
+			Help: "1 if the EFS mount target is available, 0 otherwise.",
+		},
+		[]string{"FileSystemId", "MountTargetId", "SubnetId", "AvailabilityZoneName", "LifeCycleState"},
+	)
+	reg.MustRegister(mountTarget)
+
+	// Page through the mount targets of each file system
+	for _, f := range fileSystems {
+		mountTargets := make([]*efs.MountTargetDescription, 0)
+		err := svc.DescribeMountTargetsPages(&efs.DescribeMountTargetsInput{
+			FileSystemId: f.FileSystemId,
+		},
+			func(page *efs.DescribeMountTargetsOutput, lastPage bool) bool {
+				mountTargets = append(mountTargets, page.MountTargets...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		for _, m := range mountTargets {
+			state := 0.0
+			if aws.StringValue(m.LifeCycleState) == efs.LifeCycleStateAvailable {
+				state = 1
+			}
+			mountTarget.WithLabelValues(aws.StringValue(m.FileSystemId), aws.StringValue(m.MountTargetId), aws.StringValue(m.SubnetId), aws.StringValue(m.AvailabilityZoneName), aws.StringValue(m.LifeCycleState)).Set(state)
+		}
+	}
+	return nil
+}
+
+// Lists all Elastic IP tags in us-west-2
+func get_eip_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	result, err := svc.DescribeAddresses(nil)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the addresses, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.Addresses {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each address and pupulate address map
+	address := make(map[string]map[string]string)
+	for _, f := range result.Addresses {
+		// Initialize the map for this address
+		address[*f.PublicIp] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			address[*f.PublicIp][key] = ""
+		}
+
+		// Add metadata as tags
+		address[*f.PublicIp]["AllocationId"] = aws.StringValue(f.AllocationId)
+		address[*f.PublicIp]["AssociationId"] = aws.StringValue(f.AssociationId)
+		address[*f.PublicIp]["Domain"] = aws.StringValue(f.Domain)
+
+		// Populate the address's map with the tag values
+		for _, t := range f.Tags {
+			address[*f.PublicIp][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per address
+	eip := new_collector_result(reg, "aws_eip_tags", "Key:Value metric per Elastic IP with all tags. 1 if associated, 0 if unassociated.", "PublicIp", address)
+	for key, value := range address {
+		if value["AssociationId"] != "" {
+			eip.Set(key, 1)
+		} else {
+			eip.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Elastic Beanstalk environment tags and health in us-west-2
+func get_elasticbeanstalk_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Elastic Beanstalk service client
+	svc := elasticbeanstalk.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the environments that have not been deleted
+	environments := make([]*elasticbeanstalk.EnvironmentDescription, 0)
+	input := &elasticbeanstalk.DescribeEnvironmentsInput{
+		IncludeDeleted: aws.Bool(false),
+	}
+	for {
+		result, err := svc.DescribeEnvironments(input)
+		if err != nil {
+			return err
+		}
+		environments = append(environments, result.Environments...)
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	// Iterate through all the environments, gather the tag names and add them to the tags map
+	// Keep the tags for each environment so we only list them once
+	tags := make(map[string]string)
+	environmentTags := make(map[string][]*elasticbeanstalk.Tag)
+	for _, f := range environments {
+		// Create input for ListTagsForResource method
+		input := &elasticbeanstalk.ListTagsForResourceInput{
+			ResourceArn: f.EnvironmentArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		environmentTags[*f.EnvironmentId] = resultTags.ResourceTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.ResourceTags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each environment and pupulate environment map
+	environment := make(map[string]map[string]string)
+	for _, f := range environments {
+		// Initialize the map for this environment
+		environment[*f.EnvironmentId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			environment[*f.EnvironmentId][key] = ""
+		}
+
+		// Add metadata as tags
+		environment[*f.EnvironmentId]["EnvironmentName"] = aws.StringValue(f.EnvironmentName)
+		environment[*f.EnvironmentId]["ApplicationName"] = aws.StringValue(f.ApplicationName)
+		environment[*f.EnvironmentId]["Status"] = aws.StringValue(f.Status)
+		environment[*f.EnvironmentId]["Health"] = aws.StringValue(f.Health)
+
+		// Populate the environment's map with the tag values
+		for _, t := range environmentTags[*f.EnvironmentId] {
+			environment[*f.EnvironmentId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag
+	environmentGauge := new_collector_result(reg, "aws_elasticbeanstalk_environment_tags", "Key:Value metric per Elastic Beanstalk environment with all tags. 1 if Ready, 0 otherwise.", "EnvironmentId", environment)
+
+	// Create and register a new gauge for the health of each environment
+	healthGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_elasticbeanstalk_health_status",
+			Help: "Health of each Elastic Beanstalk environment. Green=3, Yellow=2, Red=1, Grey=0.",
+		},
+		[]string{"EnvironmentId", "EnvironmentName", "ApplicationName"},
+	)
+	reg.MustRegister(healthGauge)
+
+	// The metric was first exposed as aws_elasticbeanstalk_environment_health, keep it until dashboards move over
+	deprecatedHealthGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_elasticbeanstalk_environment_health",
+			Help: "Deprecated, use aws_elasticbeanstalk_health_status. Health of each Elastic Beanstalk environment. Green=3, Yellow=2, Red=1, Grey=0.",
+		},
+		[]string{"EnvironmentId", "EnvironmentName", "ApplicationName"},
+	)
+	reg.MustRegister(deprecatedHealthGauge)
+
+	// Create and register a new gauge for the number of health causes of each environment
+	causesGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_elasticbeanstalk_causes_count",
+			Help: "Number of causes for the current health of each Elastic Beanstalk environment.",
+		},
+		[]string{"EnvironmentId", "EnvironmentName", "ApplicationName"},
+	)
+	reg.MustRegister(causesGauge)
+
+	// Map the environment health colors to a numeric value
+	health := map[string]float64{
+		elasticbeanstalk.EnvironmentHealthGreen:  3,
+		elasticbeanstalk.EnvironmentHealthYellow: 2,
+		elasticbeanstalk.EnvironmentHealthRed:    1,
+		elasticbeanstalk.EnvironmentHealthGrey:   0,
+	}
+
+	// Create one metric per environment
+	for key, value := range environment {
+		if value["Status"] == elasticbeanstalk.EnvironmentStatusReady {
+			environmentGauge.Set(key, 1)
+		} else {
+			environmentGauge.Set(key, 0)
+		}
+
+		healthGauge.WithLabelValues(key, value["EnvironmentName"], value["ApplicationName"]).Set(health[value["Health"]])
+		deprecatedHealthGauge.WithLabelValues(key, value["EnvironmentName"], value["ApplicationName"]).Set(health[value["Health"]])
+	}
+
+	// The causes are only reported for environments with enhanced health reporting
+	for _, f := range environments {
+		result, err := svc.DescribeEnvironmentHealth(&elasticbeanstalk.DescribeEnvironmentHealthInput{
+			EnvironmentId:  f.EnvironmentId,
+			AttributeNames: []*string{aws.String(elasticbeanstalk.EnvironmentHealthAttributeCauses)},
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elasticbeanstalk.ErrCodeInvalidRequestException {
+				continue
+			}
+			return err
+		}
+		causesGauge.WithLabelValues(aws.StringValue(f.EnvironmentId), aws.StringValue(f.EnvironmentName), aws.StringValue(f.ApplicationName)).Set(float64(len(result.Causes)))
+	}
+	return nil
+}
+
+// Lists all instances in an elb in us-west-2
+func get_elb_membership(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create ELB service client
+	svc := elb.New(sess, &aws.Config{Region: aws.String(region)})
+
+	result, err := svc.DescribeLoadBalancers(nil)
+
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for prometheus
+	elb := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_elb_instances",
+			Help: "Metric per EC2 instances assignd to an ELB.",
+		},
+		[]string{"LoadBalancerName", "DNSName", "InstanceId"},
+	)
+	reg.MustRegister(elb)
+
+	// Iterate through all groups, gather instances adding a metric for each
+	count := 0
+	for _, f := range result.LoadBalancerDescriptions {
+		if !elb_filter_match(f) {
+			continue
+		}
+		count++
+		for _, v := range f.Instances {
+			elb.WithLabelValues(aws.StringValue(f.LoadBalancerName), aws.StringValue(f.DNSName), *v.InstanceId).Set(1)
+		}
+	}
+	count_resources(reg, count)
+	return nil
+}
+
+// Classic ELBs cannot be filtered by the API, so --elb-filter is matched against each load balancer
+// A load balancer must match one value of every filter
+func elb_filter_match(lb *elb.LoadBalancerDescription) bool {
+	for _, f := range elbFilters {
+		value := aws.StringValue(lb.LoadBalancerName)
+		if f.Name == "vpc-id" {
+			value = aws.StringValue(lb.VPCId)
+		}
+
+		matched := false
+		for _, v := range f.Values {
+			if v == value {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Lists all EC2 network interface tags in us-west-2
+func get_eni_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the network interfaces, their tags are part of the response
+	interfaces := make([]*ec2.NetworkInterface, 0)
+	err := svc.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{},
+		func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			interfaces = append(interfaces, page.NetworkInterfaces...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the network interfaces, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range interfaces {
+		for _, v := range f.TagSet {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each network interface and pupulate eni map
+	eni := make(map[string]map[string]string)
+	for _, f := range interfaces {
+		// Unattached network interfaces have no attachment
+		attachmentInstanceId := ""
+		if f.Attachment != nil {
+			attachmentInstanceId = aws.StringValue(f.Attachment.InstanceId)
+		}
+
+		// Initialize the map for this network interface
+		eni[*f.NetworkInterfaceId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			eni[*f.NetworkInterfaceId][key] = ""
+		}
+
+		// Add metadata as tags
+		eni[*f.NetworkInterfaceId]["InterfaceType"] = aws.StringValue(f.InterfaceType)
+		eni[*f.NetworkInterfaceId]["Status"] = aws.StringValue(f.Status)
+		eni[*f.NetworkInterfaceId]["SubnetId"] = aws.StringValue(f.SubnetId)
+		eni[*f.NetworkInterfaceId]["VpcId"] = aws.StringValue(f.VpcId)
+		eni[*f.NetworkInterfaceId]["PrivateIpAddress"] = aws.StringValue(f.PrivateIpAddress)
+		eni[*f.NetworkInterfaceId]["AttachmentInstanceId"] = attachmentInstanceId
+
+		// Populate the network interface's map with the tag values
+		for _, t := range f.TagSet {
+			eni[*f.NetworkInterfaceId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, network interfaces in use are 1 and unattached ones are 0
+	eniGauge := new_collector_result(reg, "aws_eni_tags", "Key:Value metric per EC2 network interface with all tags, 1 if the interface is in use.", "NetworkInterfaceId", eni)
+	for _, f := range interfaces {
+		if aws.StringValue(f.Status) == ec2.NetworkInterfaceStatusInUse {
+			eniGauge.Set(*f.NetworkInterfaceId, 1)
+		} else {
+			eniGauge.Set(*f.NetworkInterfaceId, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all EventBridge rule and custom event bus tags in us-west-2
+func get_eventbridge_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EventBridge service client
+	svc := eventbridge.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the event buses
+	buses := make([]*eventbridge.EventBus, 0)
+	busInput := &eventbridge.ListEventBusesInput{}
+	for {
+		result, err := svc.ListEventBuses(busInput)
+		if err != nil {
+			return err
+		}
+		buses = append(buses, result.EventBuses...)
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		busInput.NextToken = result.NextToken
+	}
+
+	// Page through the rules of every event bus, ListRules only covers one bus per call
+	rules := make([]*eventbridge.Rule, 0)
+	for _, b := range buses {
+		ruleInput := &eventbridge.ListRulesInput{
+			EventBusName: b.Name,
+		}
+		for {
+			result, err := svc.ListRules(ruleInput)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, result.Rules...)
+			if aws.StringValue(result.NextToken) == "" {
+				break
+			}
+			ruleInput.NextToken = result.NextToken
+		}
+	}
+
+	// Iterate through all the rules, gather the tag names and add them to the tags map
+	// Keep the tags for each rule so we only list them once
+	tags := make(map[string]string)
+	ruleTags := make(map[string][]*eventbridge.Tag)
+	for _, f := range rules {
+		// Create input for ListTagsForResource method
+		input := &eventbridge.ListTagsForResourceInput{
+			ResourceARN: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		ruleTags[*f.Arn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each rule and pupulate rule map
+	rule := make(map[string]map[string]string)
+	for _, f := range rules {
+		// Initialize the map for this rule
+		rule[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			rule[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		rule[*f.Arn]["Name"] = aws.StringValue(f.Name)
+		rule[*f.Arn]["State"] = aws.StringValue(f.State)
+		rule[*f.Arn]["EventBusName"] = aws.StringValue(f.EventBusName)
+
+		// Populate the rule's map with the tag values
+		for _, t := range ruleTags[*f.Arn] {
+			rule[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per rule
+	ruleGauge := new_collector_result(reg, "aws_eventbridge_rule_tags", "Key:Value metric per EventBridge rule with all tags. 1 if ENABLED, 0 if DISABLED.", "Arn", rule)
+	for key, value := range rule {
+		if value["State"] == eventbridge.RuleStateEnabled {
+			ruleGauge.Set(key, 1)
+		} else {
+			ruleGauge.Set(key, 0)
+		}
+	}
+
+	// Only custom event buses are of interest, skip the default bus
+	customBuses := make([]*eventbridge.EventBus, 0, len(buses))
+	for _, b := range buses {
+		if aws.StringValue(b.Name) != "default" {
+			customBuses = append(customBuses, b)
+		}
+	}
+
+	// Iterate through all the event buses, gather the tag names and add them to the busTagNames map
+	// Keep the tags for each event buse so we only list them once
+	busTagNames := make(map[string]string)
+	busTags := make(map[string][]*eventbridge.Tag)
+	for _, f := range customBuses {
+		// Create input for ListTagsForResource method
+		input := &eventbridge.ListTagsForResourceInput{
+			ResourceARN: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		busTags[*f.Arn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := busTagNames[*v.Key]; !ok {
+				busTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each event bus and pupulate event bus map
+	bus := make(map[string]map[string]string)
+	for _, f := range customBuses {
+		// Initialize the map for this event bus
+		bus[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range busTagNames {
+			bus[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		bus[*f.Arn]["Name"] = aws.StringValue(f.Name)
+
+		// Populate the event bus's map with the tag values
+		for _, t := range busTags[*f.Arn] {
+			bus[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per event bus
+	busGauge := new_collector_result(reg, "aws_eventbridge_bus_tags", "Key:Value metric per custom EventBridge event bus with all tags.", "Arn", bus)
+	for key := range bus {
+		busGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Fraud Detector detector and model tags in us-west-2
+func get_frauddetector_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Fraud Detector service client
+	svc := frauddetector.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the detectors
+	detectors := make([]*frauddetector.Detector, 0)
+	err := svc.GetDetectorsPages(&frauddetector.GetDetectorsInput{},
+		func(page *frauddetector.GetDetectorsOutput, lastPage bool) bool {
+			detectors = append(detectors, page.Detectors...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the detectors, gather the tag names and add them to the tags map
+	// Keep the tags and status for each detector so we only look them up once
+	tags := make(map[string]string)
+	detectorTags := make(map[string][]*frauddetector.Tag)
+	detectorStatus := make(map[string]string)
+	for _, f := range detectors {
+		resultTags, err := list_frauddetector_tags(svc, f.Arn)
+		if err != nil {
+			return err
+		}
+		detectorTags[*f.Arn] = resultTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+
+		// Detectors have no status of their own, a detector is ACTIVE when one of its versions is
+		detectorStatus[*f.Arn] = frauddetector.DetectorVersionStatusInactive
+		input := &frauddetector.DescribeDetectorInput{
+			DetectorId: f.DetectorId,
+		}
+		for {
+			result, err := svc.DescribeDetector(input)
+			if err != nil {
+				return err
+			}
+			for _, v := range result.DetectorVersionSummaries {
+				if aws.StringValue(v.Status) == frauddetector.DetectorVersionStatusActive {
+					detectorStatus[*f.Arn] = frauddetector.DetectorVersionStatusActive
+				}
+			}
+			if aws.StringValue(result.NextToken) == "" {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+	}
+
+	// Gather all tags for each detector and pupulate detector map
+	detector := make(map[string]map[string]string)
+	for _, f := range detectors {
+		// Initialize the map for this detector
+		detector[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			detector[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		detector[*f.Arn]["DetectorId"] = aws.StringValue(f.DetectorId)
+		detector[*f.Arn]["Status"] = detectorStatus[*f.Arn]
+		detector[*f.Arn]["EventTypeName"] = aws.StringValue(f.EventTypeName)
+
+		// Populate the detector's map with the tag values
+		for _, t := range detectorTags[*f.Arn] {
+			detector[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per detector
+	detectorGauge := new_collector_result(reg, "aws_frauddetector_detector_tags", "Key:Value metric per Fraud Detector detector with all tags. 1 if ACTIVE, 0 otherwise.", "Arn", detector)
+	for key, value := range detector {
+		if value["Status"] == frauddetector.DetectorVersionStatusActive {
+			detectorGauge.Set(key, 1)
+		} else {
+			detectorGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the models
+	models := make([]*frauddetector.Model, 0)
+	err = svc.GetModelsPages(&frauddetector.GetModelsInput{},
+		func(page *frauddetector.GetModelsOutput, lastPage bool) bool {
+			models = append(models, page.Models...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the models, gather the tag names and add them to the modelTagNames map
+	// Keep the tags for each model so we only list them once
+	modelTagNames := make(map[string]string)
+	modelTags := make(map[string][]*frauddetector.Tag)
+	for _, f := range models {
+		resultTags, err := list_frauddetector_tags(svc, f.Arn)
+		if err != nil {
+			return err
+		}
+		modelTags[*f.Arn] = resultTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags {
+			if _, ok := modelTagNames[*v.Key]; !ok {
+				modelTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each model and pupulate model map
+	model := make(map[string]map[string]string)
+	for _, f := range models {
+		// Initialize the map for this model
+		model[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range modelTagNames {
+			model[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		model[*f.Arn]["ModelId"] = aws.StringValue(f.ModelId)
+		model[*f.Arn]["ModelType"] = aws.StringValue(f.ModelType)
+		model[*f.Arn]["EventTypeName"] = aws.StringValue(f.EventTypeName)
+
+		// Populate the model's map with the tag values
+		for _, t := range modelTags[*f.Arn] {
+			model[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per model
+	modelGauge := new_collector_result(reg, "aws_frauddetector_model_tags", "Key:Value metric per Fraud Detector model with all tags.", "Arn", model)
+	for key := range model {
+		modelGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Page through the tags of a Fraud Detector detector or model
+func list_frauddetector_tags(svc *frauddetector.FraudDetector, arn *string) ([]*frauddetector.Tag, error) {
+	tags := make([]*frauddetector.Tag, 0)
+	err := svc.ListTagsForResourcePages(&frauddetector.ListTagsForResourceInput{
+		ResourceARN: arn,
+	},
+		func(page *frauddetector.ListTagsForResourceOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Lists all Global Accelerator tags, Global Accelerator is a global service
+func get_global_accelerator_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Global Accelerator service client, us-west-2 is the only endpoint
+	svc := globalaccelerator.New(sess, &aws.Config{Region: aws.String("us-west-2")})
+
+	// Page through all of the accelerators
+	accelerators := make([]*globalaccelerator.Accelerator, 0)
+	err := svc.ListAcceleratorsPages(&globalaccelerator.ListAcceleratorsInput{},
+		func(page *globalaccelerator.ListAcceleratorsOutput, lastPage bool) bool {
+			accelerators = append(accelerators, page.Accelerators...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the accelerators, gather the tag names and add them to the tags map
+	// Keep the tags for each accelerator so we only list them once
+	tags := make(map[string]string)
+	acceleratorTags := make(map[string][]*globalaccelerator.Tag)
+	for _, f := range accelerators {
+		// Create input for ListTagsForResource method
+		input := &globalaccelerator.ListTagsForResourceInput{
+			ResourceArn: f.AcceleratorArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		acceleratorTags[*f.AcceleratorArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each accelerator and pupulate accelerator map
+	accelerator := make(map[string]map[string]string)
+	for _, f := range accelerators {
+		// Initialize the map for this accelerator
+		accelerator[*f.AcceleratorArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			accelerator[*f.AcceleratorArn][key] = ""
+		}
+
+		// Add metadata as tags
+		accelerator[*f.AcceleratorArn]["Name"] = aws.StringValue(f.Name)
+		accelerator[*f.AcceleratorArn]["Status"] = aws.StringValue(f.Status)
+		accelerator[*f.AcceleratorArn]["DnsName"] = aws.StringValue(f.DnsName)
+
+		// Populate the accelerator's map with the tag values
+		for _, t := range acceleratorTags[*f.AcceleratorArn] {
+			accelerator[*f.AcceleratorArn][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per accelerator
+	acceleratorGauge := new_collector_result(reg, "aws_globalaccelerator_tags", "Key:Value metric per Global Accelerator with all tags. 1 if DEPLOYED, 0 if IN_PROGRESS.", "AcceleratorArn", accelerator)
+	for _, f := range accelerators {
+		if aws.StringValue(f.Status) == globalaccelerator.AcceleratorStatusDeployed {
+			acceleratorGauge.Set(*f.AcceleratorArn, 1)
+		} else {
+			acceleratorGauge.Set(*f.AcceleratorArn, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Glue job and crawler tags in us-west-2
+// Glue tags are looked up by ARN, which is built from the account
+func get_glue_tags(sess *session.Session, region string, account awsAccount, reg prometheus.Registerer) error {
+	// Create Glue service client
+	svc := glue.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the jobs
+	jobs := make([]*glue.Job, 0)
+	err := svc.GetJobsPages(&glue.GetJobsInput{},
+		func(page *glue.GetJobsOutput, lastPage bool) bool {
+			jobs = append(jobs, page.Jobs...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the jobs, gather the tag names and add them to the tags map
+	// Keep the tags for each job so we only list them once
+	tags := make(map[string]string)
+	jobTags := make(map[string]map[string]*string)
+	for _, f := range jobs {
+		// Create input for GetTags method
+		arn := fmt.Sprintf("arn:%s:glue:%s:%s:job/%s", account.partition, region, account.id, aws.StringValue(f.Name))
+		input := &glue.GetTagsInput{
+			ResourceArn: aws.String(arn),
+		}
+
+		// List out the tags
+		resultTags, err := svc.GetTags(input)
+		if err != nil {
+			return err
+		}
+		jobTags[*f.Name] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each job and pupulate job map
+	job := make(map[string]map[string]string)
+	for _, f := range jobs {
+		// The job command is optional, default it to empty
+		command := ""
+		if f.Command != nil {
+			command = aws.StringValue(f.Command.Name)
+		}
+
+		// Initialize the map for this job
+		job[*f.Name] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			job[*f.Name][key] = ""
+		}
+
+		// Add metadata as tags
+		job[*f.Name]["Role"] = aws.StringValue(f.Role)
+		job[*f.Name]["Command"] = command
+		job[*f.Name]["GlueVersion"] = aws.StringValue(f.GlueVersion)
+
+		// Populate the job's map with the tag values
+		for k, v := range jobTags[*f.Name] {
+			job[*f.Name][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per job
+	jobGauge := new_collector_result(reg, "aws_glue_job_tags", "Key:Value metric per Glue job with all tags.", "JobName", job)
+	for key := range job {
+		jobGauge.Set(key, 1)
+	}
+
+	// Page through all of the crawlers
+	crawlers := make([]*glue.Crawler, 0)
+	err = svc.GetCrawlersPages(&glue.GetCrawlersInput{},
+		func(page *glue.GetCrawlersOutput, lastPage bool) bool {
+			crawlers = append(crawlers, page.Crawlers...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the crawlers, gather the tag names and add them to the crawlerTagKeys map
+	// Keep the tags for each crawler so we only list them once
+	crawlerTagKeys := make(map[string]string)
+	crawlerTags := make(map[string]map[string]*string)
+	for _, f := range crawlers {
+		// Create input for GetTags method
+		arn := fmt.Sprintf("arn:%s:glue:%s:%s:crawler/%s", account.partition, region, account.id, aws.StringValue(f.Name))
+		input := &glue.GetTagsInput{
+			ResourceArn: aws.String(arn),
+		}
+
+		// List out the tags
+		resultTags, err := svc.GetTags(input)
+		if err != nil {
+			return err
+		}
+		crawlerTags[*f.Name] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := crawlerTagKeys[k]; !ok {
+				crawlerTagKeys[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each crawler and pupulate crawler map
+	crawler := make(map[string]map[string]string)
+	for _, f := range crawlers {
+		// Initialize the map for this crawler
+		crawler[*f.Name] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range crawlerTagKeys {
+			crawler[*f.Name][key] = ""
+		}
+
+		// Add metadata as tags
+		crawler[*f.Name]["Role"] = aws.StringValue(f.Role)
+		crawler[*f.Name]["State"] = aws.StringValue(f.State)
+		crawler[*f.Name]["DatabaseName"] = aws.StringValue(f.DatabaseName)
+
+		// Populate the crawler's map with the tag values
+		for k, v := range crawlerTags[*f.Name] {
+			crawler[*f.Name][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per crawler
+	crawlerGauge := new_collector_result(reg, "aws_glue_crawler_tags", "Key:Value metric per Glue crawler with all tags.", "CrawlerName", crawler)
+	for key := range crawler {
+		crawlerGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Ground Truth labeling job tags in us-west-2
+// Ground Truth is part of SageMaker and is served by the SageMaker API
+func get_groundtruth_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create SageMaker service client
+	svc := sagemaker.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the labeling jobs
+	jobs := make([]*sagemaker.LabelingJobSummary, 0)
+	err := svc.ListLabelingJobsPages(&sagemaker.ListLabelingJobsInput{},
+		func(page *sagemaker.ListLabelingJobsOutput, lastPage bool) bool {
+			jobs = append(jobs, page.LabelingJobSummaryList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe every labeling job, the summary has neither the tags nor the human task config
+	descriptions := make([]*sagemaker.DescribeLabelingJobOutput, 0, len(jobs))
+	for _, f := range jobs {
+		result, err := svc.DescribeLabelingJob(&sagemaker.DescribeLabelingJobInput{
+			LabelingJobName: f.LabelingJobName,
+		})
+		if err != nil {
+			return err
+		}
+		descriptions = append(descriptions, result)
+	}
+
+	// Iterate through all the labeling jobs, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range descriptions {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each labeling job and pupulate labeling job map
+	labelingJob := make(map[string]map[string]string)
+	for _, f := range descriptions {
+		// Initialize the map for this labeling job
+		labelingJob[*f.LabelingJobArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			labelingJob[*f.LabelingJobArn][key] = ""
+		}
+
+		// Add metadata as tags
+		labelingJob[*f.LabelingJobArn]["LabelingJobName"] = aws.StringValue(f.LabelingJobName)
+		labelingJob[*f.LabelingJobArn]["LabelingJobStatus"] = aws.StringValue(f.LabelingJobStatus)
+
+		// Populate the labeling job's map with the tag values
+		for _, t := range f.Tags {
+			labelingJob[*f.LabelingJobArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per labeling job
+	labelingJobGauge := new_collector_result(reg, "aws_groundtruth_labeling_job_tags", "Key:Value metric per Ground Truth labeling job with all tags. 1 if InProgress, 0 otherwise.", "LabelingJobArn", labelingJob)
+	for key, value := range labelingJob {
+		if value["LabelingJobStatus"] == sagemaker.LabelingJobStatusInProgress {
+			labelingJobGauge.Set(key, 1)
+		} else {
+			labelingJobGauge.Set(key, 0)
+		}
+	}
+
+	// Create and register a new gauge for the number of human workers labeling each data object
+	workers := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_groundtruth_labeled_count",
+			Help: "Number of human workers labeling each data object of a Ground Truth labeling job, a proxy for the job complexity.",
+		},
+		[]string{"LabelingJobName"},
+	)
+	reg.MustRegister(workers)
+	for _, f := range descriptions {
+		if f.HumanTaskConfig == nil || f.HumanTaskConfig.NumberOfHumanWorkersPerDataObject == nil {
+			continue
+		}
+		workers.WithLabelValues(aws.StringValue(f.LabelingJobName)).Set(float64(*f.HumanTaskConfig.NumberOfHumanWorkersPerDataObject))
+	}
+	return nil
+}
+
+// Count the GuardDuty findings in us-west-2 per severity band and report the status of each detector
+func get_guardduty_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create GuardDuty service client
+	svc := guardduty.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the detectors, a region has at most one
+	detectorIds := make([]*string, 0)
+	err := svc.ListDetectorsPages(&guardduty.ListDetectorsInput{},
+		func(page *guardduty.ListDetectorsOutput, lastPage bool) bool {
+			detectorIds = append(detectorIds, page.DetectorIds...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register new gauges for the status and the findings of each detector
+	status := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_guardduty_detector_status",
+			Help: "1 if the GuardDuty detector is enabled, 0 otherwise.",
+		},
+		[]string{"DetectorId"},
+	)
+	findings := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_guardduty_finding_count",
+			Help: "Number of unarchived GuardDuty findings per detector and severity.",
+		},
+		[]string{"DetectorId", "Severity"},
+	)
+	reg.MustRegister(status, findings)
+
+	// GuardDuty severities are numbers, the bands follow the low, medium and high of the console
+	bands := []struct {
+		severity string
+		gte      int64
+		lt       int64
+	}{
+		{"low", 1, 4},
+		{"medium", 4, 7},
+		{"high", 7, 9},
+	}
+
+	for _, d := range detectorIds {
+		detector, err := svc.GetDetector(&guardduty.GetDetectorInput{
+			DetectorId: d,
+		})
+		if err != nil {
+			return err
+		}
+		if aws.StringValue(detector.Status) == guardduty.DetectorStatusEnabled {
+			status.WithLabelValues(aws.StringValue(d)).Set(1)
+		} else {
+			status.WithLabelValues(aws.StringValue(d)).Set(0)
+		}
+
+		// Page through the unarchived findings of each band and count them
+		for _, b := range bands {
+			input := &guardduty.ListFindingsInput{
+				DetectorId: d,
+				FindingCriteria: &guardduty.FindingCriteria{
+					Criterion: map[string]*guardduty.Condition{
+						"service.archived": {
+							Eq: aws.StringSlice([]string{"false"}),
+						},
+						"severity": {
+							GreaterThanOrEqual: aws.Int64(b.gte),
+							LessThan:           aws.Int64(b.lt),
+						},
+					},
+				},
+			}
+			count := 0
+			err := svc.ListFindingsPages(input,
+				func(page *guardduty.ListFindingsOutput, lastPage bool) bool {
+					count += len(page.FindingIds)
+					return true
+				})
+			if err != nil {
+				return err
+			}
+			findings.WithLabelValues(aws.StringValue(d), b.severity).Set(float64(count))
+		}
+	}
+	count_resources(reg, len(detectorIds))
+	return nil
+}
+
+// Lists all HealthLake data store tags in us-west-2
+func get_healthlake_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create HealthLake service client
+	svc := healthlake.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the data stores
+	datastores := make([]*healthlake.DatastoreProperties, 0)
+	err := svc.ListFHIRDatastoresPages(&healthlake.ListFHIRDatastoresInput{},
+		func(page *healthlake.ListFHIRDatastoresOutput, lastPage bool) bool {
+			datastores = append(datastores, page.DatastorePropertiesList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the data stores, gather the tag names and add them to the tags map
+	// Keep the tags for each data store so we only list them once
+	tags := make(map[string]string)
+	datastoreTags := make(map[string][]*healthlake.Tag)
+	for _, f := range datastores {
+		// Create input for ListTagsForResource method
+		input := &healthlake.ListTagsForResourceInput{
+			ResourceARN: f.DatastoreArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		datastoreTags[*f.DatastoreArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each data store and pupulate data store map
+	datastore := make(map[string]map[string]string)
+	for _, f := range datastores {
+		// Initialize the map for this data store
+		datastore[*f.DatastoreArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			datastore[*f.DatastoreArn][key] = ""
+		}
+
+		// Add metadata as tags
+		datastore[*f.DatastoreArn]["DatastoreId"] = aws.StringValue(f.DatastoreId)
+		datastore[*f.DatastoreArn]["DatastoreName"] = aws.StringValue(f.DatastoreName)
+		datastore[*f.DatastoreArn]["DatastoreStatus"] = aws.StringValue(f.DatastoreStatus)
+		datastore[*f.DatastoreArn]["DatastoreTypeVersion"] = aws.StringValue(f.DatastoreTypeVersion)
+
+		// Populate the data store's map with the tag values
+		for _, t := range datastoreTags[*f.DatastoreArn] {
+			datastore[*f.DatastoreArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per data store
+	datastoreGauge := new_collector_result(reg, "aws_healthlake_datastore_tags", "Key:Value metric per HealthLake data store with all tags. 1 if ACTIVE, 0 otherwise.", "DatastoreArn", datastore)
+	for key, value := range datastore {
+		if value["DatastoreStatus"] == healthlake.DatastoreStatusActive {
+			datastoreGauge.Set(key, 1)
+		} else {
+			datastoreGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Count the active Inspector findings in us-west-2 per severity, finding type and resource type
+func get_inspector_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Inspector service client
+	svc := inspector2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Every Inspector call fails when Inspector is not enabled in the region, check it once and skip
+	// Without account ids the status of the calling account is returned, access errors are still reported
+	status, err := svc.BatchGetAccountStatus(&inspector2.BatchGetAccountStatusInput{})
+	if err != nil {
+		return err
+	}
+	if len(status.Accounts) == 0 || status.Accounts[0].State == nil || aws.StringValue(status.Accounts[0].State.Status) != inspector2.StatusEnabled {
+		log.Printf("WARNING: Inspector is not enabled in %s, skipping", region)
+		return nil
+	}
+
+	// Only count the active findings of the severities worth alerting on
+	severities := make([]*inspector2.StringFilter, 0)
+	for _, s := range []string{inspector2.SeverityCritical, inspector2.SeverityHigh, inspector2.SeverityMedium, inspector2.SeverityLow} {
+		severities = append(severities, &inspector2.StringFilter{
+			Comparison: aws.String(inspector2.StringComparisonEquals),
+			Value:      aws.String(s),
+		})
+	}
+	input := &inspector2.ListFindingsInput{
+		FilterCriteria: &inspector2.FilterCriteria{
+			Severity: severities,
+			FindingStatus: []*inspector2.StringFilter{
+				{
+					Comparison: aws.String(inspector2.StringComparisonEquals),
+					Value:      aws.String(inspector2.FindingStatusActive),
+				},
+			},
+		},
+	}
+
+	// Create and register a new gauge for the number of findings
+	findings := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_inspector_finding_count",
+			Help: "Number of active Inspector findings per severity, finding type and resource type.",
+		},
+		[]string{"Severity", "FindingType", "ResourceType"},
+	)
+	reg.MustRegister(findings)
+
+	// Page through all of the findings and count them
+	count := 0
+	err = svc.ListFindingsPages(input,
+		func(page *inspector2.ListFindingsOutput, lastPage bool) bool {
+			count += len(page.Findings)
+			for _, f := range page.Findings {
+				// A finding covers a single resource
+				resourceType := ""
+				if len(f.Resources) > 0 {
+					resourceType = aws.StringValue(f.Resources[0].Type)
+				}
+				findings.WithLabelValues(aws.StringValue(f.Severity), aws.StringValue(f.Type), resourceType).Inc()
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	count_resources(reg, count)
+	return nil
+}
+
+// Lists all IoT thing group and thing type tags in us-west-2
+func get_iot_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create IoT service client
+	svc := iot.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the thing groups
+	thingGroups := make([]*iot.GroupNameAndArn, 0)
+	err := svc.ListThingGroupsPages(&iot.ListThingGroupsInput{},
+		func(page *iot.ListThingGroupsOutput, lastPage bool) bool {
+			thingGroups = append(thingGroups, page.ThingGroups...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// The group id is only returned by DescribeThingGroup
+	groupIds := make(map[string]string)
+	for _, f := range thingGroups {
+		result, err := svc.DescribeThingGroup(&iot.DescribeThingGroupInput{
+			ThingGroupName: f.GroupName,
+		})
+		if err != nil {
+			return err
+		}
+		groupIds[*f.GroupName] = aws.StringValue(result.ThingGroupId)
+	}
+
+	// Iterate through all the thing groups, gather the tag names and add them to the groupTagNames map
+	// Keep the tags for each thing group so we only list them once
+	groupTagNames := make(map[string]string)
+	groupTags := make(map[string][]*iot.Tag)
+	for _, f := range thingGroups {
+		// List out the tags
+		resultTags, err := list_iot_tags(svc, aws.StringValue(f.GroupArn))
+		if err != nil {
+			return err
+		}
+		groupTags[*f.GroupName] = resultTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags {
+			if _, ok := groupTagNames[*v.Key]; !ok {
+				groupTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each thing group and pupulate thing group map
+	thingGroup := make(map[string]map[string]string)
+	for _, f := range thingGroups {
+		// Initialize the map for this thing group
+		thingGroup[*f.GroupName] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range groupTagNames {
+			thingGroup[*f.GroupName][key] = ""
+		}
+
+		// Add metadata as tags
+		thingGroup[*f.GroupName]["GroupArn"] = aws.StringValue(f.GroupArn)
+		thingGroup[*f.GroupName]["GroupId"] = groupIds[*f.GroupName]
+
+		// Populate the thing group's map with the tag values
+		for _, t := range groupTags[*f.GroupName] {
+			thingGroup[*f.GroupName][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per thing group
+	groupGauge := new_collector_result(reg, "aws_iot_thing_group_tags", "Key:Value metric per IoT thing group with all tags.", "GroupName", thingGroup)
+	for key := range thingGroup {
+		groupGauge.Set(key, 1)
+	}
+
+	// Page through all of the thing types
+	thingTypes := make([]*iot.ThingTypeDefinition, 0)
+	err = svc.ListThingTypesPages(&iot.ListThingTypesInput{},
+		func(page *iot.ListThingTypesOutput, lastPage bool) bool {
+			thingTypes = append(thingTypes, page.ThingTypes...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the thing types, gather the tag names and add them to the typeTagNames map
+	// Keep the tags for each thing type so we only list them once
+	typeTagNames := make(map[string]string)
+	typeTags := make(map[string][]*iot.Tag)
+	for _, f := range thingTypes {
+		// List out the tags
+		resultTags, err := list_iot_tags(svc, aws.StringValue(f.ThingTypeArn))
+		if err != nil {
+			return err
+		}
+		typeTags[*f.ThingTypeName] = resultTags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags {
+			if _, ok := typeTagNames[*v.Key]; !ok {
+				typeTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each thing type and pupulate thing type map
+	thingType := make(map[string]map[string]string)
+	for _, f := range thingTypes {
+		// Initialize the map for this thing type
+		thingType[*f.ThingTypeName] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range typeTagNames {
+			thingType[*f.ThingTypeName][key] = ""
+		}
+
+		// Add metadata as tags
+		thingType[*f.ThingTypeName]["ThingTypeArn"] = aws.StringValue(f.ThingTypeArn)
+
+		// Populate the thing type's map with the tag values
+		for _, t := range typeTags[*f.ThingTypeName] {
+			thingType[*f.ThingTypeName][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per thing type
+	typeGauge := new_collector_result(reg, "aws_iot_thing_type_tags", "Key:Value metric per IoT thing type with all tags.", "ThingTypeName", thingType)
+	for key := range thingType {
+		typeGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// List every tag of an IoT resource, ListTagsForResource is paginated
+func list_iot_tags(svc *iot.IoT, arn string) ([]*iot.Tag, error) {
+	tags := make([]*iot.Tag, 0)
+	err := svc.ListTagsForResourcePages(&iot.ListTagsForResourceInput{
+		ResourceArn: aws.String(arn),
+	},
+		func(page *iot.ListTagsForResourceOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Lists all IPAM pool tags and allocation counts in us-west-2
+func get_ipam_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the IPAM scopes
+	scopes := make([]*ec2.IpamScope, 0)
+	err := svc.DescribeIpamScopesPages(&ec2.DescribeIpamScopesInput{},
+		func(page *ec2.DescribeIpamScopesOutput, lastPage bool) bool {
+			scopes = append(scopes, page.IpamScopes...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Pools only reference their scope by ARN
+	scopeIds := make(map[string]string)
+	for _, f := range scopes {
+		scopeIds[aws.StringValue(f.IpamScopeArn)] = aws.StringValue(f.IpamScopeId)
+	}
+
+	// Page through all of the IPAM pools
+	pools := make([]*ec2.IpamPool, 0)
+	err = svc.DescribeIpamPoolsPages(&ec2.DescribeIpamPoolsInput{},
+		func(page *ec2.DescribeIpamPoolsOutput, lastPage bool) bool {
+			pools = append(pools, page.IpamPools...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the pools, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range pools {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each pool and pupulate pool map
+	pool := make(map[string]map[string]string)
+	for _, f := range pools {
+		// Initialize the map for this pool
+		pool[*f.IpamPoolId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			pool[*f.IpamPoolId][key] = ""
+		}
+
+		// Add metadata as tags
+		pool[*f.IpamPoolId]["IpamScopeId"] = scopeIds[aws.StringValue(f.IpamScopeArn)]
+		pool[*f.IpamPoolId]["AddressFamily"] = aws.StringValue(f.AddressFamily)
+		pool[*f.IpamPoolId]["State"] = aws.StringValue(f.State)
+
+		// Populate the pool's map with the tag values
+		for _, t := range f.Tags {
+			pool[*f.IpamPoolId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per pool
+	poolGauge := new_collector_result(reg, "aws_ipam_pool_tags", "Key:Value metric per IPAM pool with all tags.", "IpamPoolId", pool)
+	for key := range pool {
+		poolGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the number of allocations in each pool
+	allocatedCidrs := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ipam_pool_allocated_cidrs",
+			Help: "Number of CIDR allocations in each IPAM pool.",
+		},
+		[]string{"IpamPoolId", "IpamScopeId"},
+	)
+	reg.MustRegister(allocatedCidrs)
+
+	for _, f := range pools {
+		// Page through the allocations of this pool and count them
+		count := 0
+		err := svc.GetIpamPoolAllocationsPages(&ec2.GetIpamPoolAllocationsInput{
+			IpamPoolId: f.IpamPoolId,
+		},
+			func(page *ec2.GetIpamPoolAllocationsOutput, lastPage bool) bool {
+				count += len(page.IpamPoolAllocations)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+		allocatedCidrs.WithLabelValues(aws.StringValue(f.IpamPoolId), scopeIds[aws.StringValue(f.IpamScopeArn)]).Set(float64(count))
+	}
+	return nil
+}
+
+// Lists all Lake Formation tags and the databases and tables they are attached to in us-west-2
+// ListResources only returns the registered storage locations, the catalog resources are found by searching on every LF tag key
+func get_lakeformation_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Lake Formation service client
+	svc := lakeformation.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the LF tags
+	lfTags := make([]*lakeformation.LFTagPair, 0)
+	err := svc.ListLFTagsPages(&lakeformation.ListLFTagsInput{},
+		func(page *lakeformation.ListLFTagsOutput, lastPage bool) bool {
+			lfTags = append(lfTags, page.LFTags...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for the number of LF tag keys
+	keyCount := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aws_lakeformation_tag_key_count",
+			Help: "Number of Lake Formation tag keys defined.",
+		},
+	)
+	reg.MustRegister(keyCount)
+	keyCount.Set(float64(len(lfTags)))
+
+	// Search the databases and tables carrying each LF tag key with any of its values
+	// A resource with several LF tags is found once per key and is only kept once
+	resourceTags := make(map[string][]*lakeformation.LFTagPair)
+	resource := make(map[string]map[string]string)
+	for _, t := range lfTags {
+		expression := []*lakeformation.LFTag{
+			{
+				TagKey:    t.TagKey,
+				TagValues: t.TagValues,
+			},
+		}
+
+		err := svc.SearchDatabasesByLFTagsPages(&lakeformation.SearchDatabasesByLFTagsInput{
+			Expression: expression,
+		},
+			func(page *lakeformation.SearchDatabasesByLFTagsOutput, lastPage bool) bool {
+				for _, d := range page.DatabaseList {
+					if d.Database == nil {
+						continue
+					}
+					id := "DATABASE/" + aws.StringValue(d.Database.Name)
+					resourceTags[id] = d.LFTags
+					resource[id] = map[string]string{
+						"ResourceType": "DATABASE",
+						"DatabaseName": aws.StringValue(d.Database.Name),
+						"TableName":    "",
+					}
+				}
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		err = svc.SearchTablesByLFTagsPages(&lakeformation.SearchTablesByLFTagsInput{
+			Expression: expression,
+		},
+			func(page *lakeformation.SearchTablesByLFTagsOutput, lastPage bool) bool {
+				for _, f := range page.TableList {
+					if f.Table == nil {
+						continue
+					}
+					id := "TABLE/" + aws.StringValue(f.Table.DatabaseName) + "/" + aws.StringValue(f.Table.Name)
+					resourceTags[id] = f.LFTagsOnTable
+					resource[id] = map[string]string{
+						"ResourceType": "TABLE",
+						"DatabaseName": aws.StringValue(f.Table.DatabaseName),
+						"TableName":    aws.StringValue(f.Table.Name),
+					}
+				}
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Populate each resource's map with every LF tag key and its tag values
+	for id, value := range resource {
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for _, t := range lfTags {
+			value[aws.StringValue(t.TagKey)] = ""
+		}
+
+		// Populate the resource's map with the tag values, a key can hold several values
+		for _, t := range resourceTags[id] {
+			value[aws.StringValue(t.TagKey)] = strings.Join(aws.StringValueSlice(t.TagValues), ",")
+		}
+	}
+
+	// Register a gauge labelled with every LF tag and create one metric per resource
+	resourceGauge := new_collector_result(reg, "aws_lakeformation_resource_tags", "Key:Value metric per Lake Formation database and table with all LF tags.", "", resource)
+	for key := range resource {
+		resourceGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Lambda functions in us-west-2
+func get_lambda_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Lambda service client
+	svc := lambda.New(sess, &aws.Config{Region: aws.String(region)})
+
+	result, err := svc.ListFunctions(nil)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the functions, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.Functions {
+		// Create input for ListTags method
+		arn := aws.StringValue(f.FunctionArn)
+		input := &lambda.ListTagsInput{
+			Resource: aws.String(arn),
+		}
+		// List out the tags
+		resultTags, err := svc.ListTags(input)
+		if err != nil {
+			return err
+		}
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each function and pupulate function map
+	function := make(map[string]map[string]string)
+	// Iterate through all the functions and create one entry with all tags
+	for _, f := range result.Functions {
+		// Create input for ListTags method
+		arn := aws.StringValue(f.FunctionArn)
+		input := &lambda.ListTagsInput{
+			Resource: aws.String(arn),
+		}
+		// List out the tags
+		resultTags, err := svc.ListTags(input)
+		if err != nil {
+			return err
+		}
+
+		// Initialize the map for this FileSystem
+		function[*f.FunctionArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			function[*f.FunctionArn][key] = ""
+		}
+
+		// Add metadata as tags
+		function[*f.FunctionArn]["FunctionName"] = aws.StringValue(f.FunctionName)
+		function[*f.FunctionArn]["Description"] = aws.StringValue(f.Description)
+
+		// Populate the function's map with the tag values
+		for k, v := range resultTags.Tags {
+			function[*f.FunctionArn][k] = *v
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per filesystem
+	lambda := new_collector_result(reg, "aws_lambda_tags", "Key:Value metric per Lambda function with all tags.", "FunctionArn", function)
+	for key := range function {
+		lambda.Set(key, 1)
+	}
+
+	// Reuse the listed functions for the concurrency metrics
+	return get_lambda_concurrency(svc, result.Functions, reg)
+}
+
+// Lists the reserved and provisioned concurrency of the given Lambda functions
+func get_lambda_concurrency(svc *lambda.Lambda, functions []*lambda.FunctionConfiguration, reg prometheus.Registerer) error {
+	// Create and register a new gauge for the reserved concurrency of each function
+	reserved := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_lambda_reserved_concurrency",
+			Help: "Reserved concurrency of each Lambda function, -1 if it uses the unreserved account concurrency.",
+		},
+		[]string{"FunctionName"},
+	)
+	reg.MustRegister(reserved)
+
+	// Create and register a new gauge for the provisioned concurrency of each function version or alias
+	provisioned := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_lambda_provisioned_concurrency",
+			Help: "Allocated provisioned concurrency of each Lambda function version or alias.",
+		},
+		[]string{"FunctionName", "Qualifier"},
+	)
+	reg.MustRegister(provisioned)
+
+	for _, f := range functions {
+		result, err := svc.GetFunctionConcurrency(&lambda.GetFunctionConcurrencyInput{
+			FunctionName: f.FunctionName,
+		})
+		if err != nil {
+			return err
+		}
+		if result.ReservedConcurrentExecutions == nil {
+			reserved.WithLabelValues(aws.StringValue(f.FunctionName)).Set(-1)
+		} else {
+			reserved.WithLabelValues(aws.StringValue(f.FunctionName)).Set(float64(*result.ReservedConcurrentExecutions))
+		}
+
+		// Page through the provisioned concurrency of every version and alias
+		configs := make([]*lambda.ProvisionedConcurrencyConfigListItem, 0)
+		err = svc.ListProvisionedConcurrencyConfigsPages(&lambda.ListProvisionedConcurrencyConfigsInput{
+			FunctionName: f.FunctionName,
+		},
+			func(page *lambda.ListProvisionedConcurrencyConfigsOutput, lastPage bool) bool {
+				configs = append(configs, page.ProvisionedConcurrencyConfigs...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		// The qualifier is the last part of the ARN, arn:aws:lambda:region:account:function:name:qualifier
+		for _, c := range configs {
+			arn := strings.Split(aws.StringValue(c.FunctionArn), ":")
+			qualifier := arn[len(arn)-1]
+			provisioned.WithLabelValues(aws.StringValue(f.FunctionName), qualifier).Set(float64(aws.Int64Value(c.AllocatedProvisionedConcurrentExecutions)))
+		}
+	}
+	return nil
+}
+
+// Lists all Lightsail instance tags in us-west-2
+// Lightsail is not available in every region
+func get_lightsail_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Lightsail service client
+	svc := lightsail.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the instances
+	instances := make([]*lightsail.Instance, 0)
+	input := &lightsail.GetInstancesInput{}
+	for {
+		result, err := svc.GetInstances(input)
+		if err != nil {
+			return err
+		}
+		instances = append(instances, result.Instances...)
+		if aws.StringValue(result.NextPageToken) == "" {
+			break
+		}
+		input.PageToken = result.NextPageToken
+	}
+
+	// Iterate through all the instances, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range instances {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each instance and pupulate instance map
+	instance := make(map[string]map[string]string)
+	for _, f := range instances {
+		// The instance state is optional, default it to empty
+		state := ""
+		if f.State != nil {
+			state = aws.StringValue(f.State.Name)
+		}
+
+		// Initialize the map for this instance
+		instance[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			instance[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		instance[*f.Arn]["Name"] = aws.StringValue(f.Name)
+		instance[*f.Arn]["BlueprintId"] = aws.StringValue(f.BlueprintId)
+		instance[*f.Arn]["BundleId"] = aws.StringValue(f.BundleId)
+		instance[*f.Arn]["State"] = state
+
+		// Populate the instance's map with the tag values
+		for _, t := range f.Tags {
+			instance[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per instance
+	lightsailGauge := new_collector_result(reg, "aws_lightsail_instance_tags", "Key:Value metric per Lightsail instance with all tags. 1 if running, 0 otherwise. Lightsail availability varies by region.", "Arn", instance)
+	for key, value := range instance {
+		if value["State"] == "running" {
+			lightsailGauge.Set(key, 1)
+		} else {
+			lightsailGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Macie answers with an AccessDeniedException both when it is not enabled and when the caller lacks
+// permissions, only the message tells them apart
+func macie_not_enabled(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == macie2.ErrCodeAccessDeniedException && strings.Contains(strings.ToLower(aerr.Message()), "macie is not enabled")
+}
+
+// Lists all Macie classification job tags in us-west-2
+func get_macie_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Macie service client
+	svc := macie2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Every Macie call fails when Macie is not enabled in the region, check it once and skip
+	_, err := svc.GetMacieSession(&macie2.GetMacieSessionInput{})
+	if err != nil {
+		if macie_not_enabled(err) {
+			log.Printf("WARNING: Macie is not enabled in %s, skipping", region)
+			return nil
+		}
+		return err
+	}
+
+	// Page through all of the classification jobs
+	jobs := make([]*macie2.JobSummary, 0)
+	err = svc.ListClassificationJobsPages(&macie2.ListClassificationJobsInput{},
+		func(page *macie2.ListClassificationJobsOutput, lastPage bool) bool {
+			jobs = append(jobs, page.Items...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the jobs, gather the tag names and add them to the tags map
+	// Keep the tags for each job so we only list them once
+	tags := make(map[string]string)
+	jobTags := make(map[string]map[string]*string)
+	for _, f := range jobs {
+		// Create input for DescribeClassificationJob method
+		input := &macie2.DescribeClassificationJobInput{
+			JobId: f.JobId,
+		}
+
+		// Describe the job, the tags are part of the response
+		resultJob, err := svc.DescribeClassificationJob(input)
+		if err != nil {
+			return err
+		}
+		jobTags[*f.JobId] = resultJob.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultJob.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each job and pupulate job map
+	job := make(map[string]map[string]string)
+	for _, f := range jobs {
+		// Initialize the map for this job
+		job[*f.JobId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			job[*f.JobId][key] = ""
+		}
+
+		// Add metadata as tags
+		job[*f.JobId]["Name"] = aws.StringValue(f.Name)
+		job[*f.JobId]["JobStatus"] = aws.StringValue(f.JobStatus)
+		job[*f.JobId]["JobType"] = aws.StringValue(f.JobType)
+
+		// Populate the job's map with the tag values
+		for k, v := range jobTags[*f.JobId] {
+			job[*f.JobId][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag, running and complete jobs are 1, cancelled and paused jobs are 0
+	jobGauge := new_collector_result(reg, "aws_macie_classification_job_tags", "Key:Value metric per Macie classification job with all tags, 1 if the job is running or complete.", "JobId", job)
+	for _, f := range jobs {
+		switch aws.StringValue(f.JobStatus) {
+		case macie2.JobStatusRunning, macie2.JobStatusComplete:
+			jobGauge.Set(*f.JobId, 1)
+		default:
+			jobGauge.Set(*f.JobId, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all MediaConvert queue tags in us-west-2
+func get_mediaconvert_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create MediaConvert service client
+	svc := mediaconvert.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// MediaConvert needs the account specific endpoint of the region
+	endpoints, err := svc.DescribeEndpoints(&mediaconvert.DescribeEndpointsInput{})
+	if err != nil {
+		return err
+	}
+	if len(endpoints.Endpoints) == 0 {
+		return nil
+	}
+	svc = mediaconvert.New(sess, &aws.Config{
+		Region:   aws.String(region),
+		Endpoint: endpoints.Endpoints[0].Url,
+	})
+
+	// Page through all of the queues
+	queues := make([]*mediaconvert.Queue, 0)
+	err = svc.ListQueuesPages(&mediaconvert.ListQueuesInput{},
+		func(page *mediaconvert.ListQueuesOutput, lastPage bool) bool {
+			queues = append(queues, page.Queues...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the queues, gather the tag names and add them to the tags map
+	// Keep the tags for each queue so we only list them once
+	tags := make(map[string]string)
+	queueTags := make(map[string]map[string]*string)
+	for _, f := range queues {
+		// Create input for ListTagsForResource method
+		input := &mediaconvert.ListTagsForResourceInput{
+			Arn: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		queueTags[*f.Arn] = resultTags.ResourceTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.ResourceTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each queue and pupulate queue map
+	queue := make(map[string]map[string]string)
+	for _, f := range queues {
+		// Initialize the map for this queue
+		queue[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			queue[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		queue[*f.Arn]["Name"] = aws.StringValue(f.Name)
+		queue[*f.Arn]["Status"] = aws.StringValue(f.Status)
+		queue[*f.Arn]["Type"] = aws.StringValue(f.Type)
+		queue[*f.Arn]["PricingPlan"] = aws.StringValue(f.PricingPlan)
+
+		// Populate the queue's map with the tag values
+		for k, v := range queueTags[*f.Arn] {
+			queue[*f.Arn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per queue
+	queueGauge := new_collector_result(reg, "aws_mediaconvert_queue_tags", "Key:Value metric per MediaConvert queue with all tags. 1 if ACTIVE, 0 if PAUSED.", "Arn", queue)
+	for key, value := range queue {
+		if value["Status"] == mediaconvert.QueueStatusActive {
+			queueGauge.Set(key, 1)
+		} else {
+			queueGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all MSK (Managed Kafka) cluster tags and broker counts in us-west-2
+func get_msk_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create MSK service client
+	svc := kafka.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the clusters
+	clusters := make([]*kafka.ClusterInfo, 0)
+	err := svc.ListClustersPages(&kafka.ListClustersInput{},
+		func(page *kafka.ListClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.ClusterInfoList...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the clusters, gather the tag names and add them to the tags map
+	// Keep the tags for each cluster so we only list them once
+	tags := make(map[string]string)
+	clusterTags := make(map[string]map[string]*string)
+	for _, f := range clusters {
+		// Create input for ListTagsForResource method
+		input := &kafka.ListTagsForResourceInput{
+			ResourceArn: f.ClusterArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		clusterTags[*f.ClusterArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each cluster and pupulate cluster map
+	cluster := make(map[string]map[string]string)
+	for _, f := range clusters {
+		// Broker software info is optional, default the kafka version to empty
+		kafkaVersion := ""
+		if f.CurrentBrokerSoftwareInfo != nil {
+			kafkaVersion = aws.StringValue(f.CurrentBrokerSoftwareInfo.KafkaVersion)
+		}
+
+		// Initialize the map for this cluster
+		cluster[*f.ClusterArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			cluster[*f.ClusterArn][key] = ""
+		}
+
+		// Add metadata as tags
+		cluster[*f.ClusterArn]["ClusterName"] = aws.StringValue(f.ClusterName)
+		cluster[*f.ClusterArn]["State"] = aws.StringValue(f.State)
+		cluster[*f.ClusterArn]["KafkaVersion"] = kafkaVersion
+		cluster[*f.ClusterArn]["NumberOfBrokerNodes"] = strconv.FormatInt(aws.Int64Value(f.NumberOfBrokerNodes), 10)
+
+		// Populate the cluster's map with the tag values
+		for k, v := range clusterTags[*f.ClusterArn] {
+			cluster[*f.ClusterArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per cluster
+	clusterGauge := new_collector_result(reg, "aws_msk_cluster_tags", "Key:Value metric per MSK cluster with all tags. 1 if ACTIVE, 0 otherwise.", "ClusterArn", cluster)
+	for key, value := range cluster {
+		if value["State"] == kafka.ClusterStateActive {
+			clusterGauge.Set(key, 1)
+		} else {
+			clusterGauge.Set(key, 0)
+		}
+	}
+
+	// Create and register a new gauge for the number of brokers in each cluster
+	brokerCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_msk_broker_count",
+			Help: "Number of broker nodes in each MSK cluster.",
+		},
+		[]string{"ClusterName", "ClusterArn"},
+	)
+	reg.MustRegister(brokerCount)
+
+	for _, f := range clusters {
+		brokerCount.WithLabelValues(aws.StringValue(f.ClusterName), aws.StringValue(f.ClusterArn)).Set(float64(aws.Int64Value(f.NumberOfBrokerNodes)))
+	}
+	return nil
+}
+
+// Lists all Neptune cluster tags in us-west-2
+// Neptune is served by the RDS API so the RDS client is shared with get_rds_tags
+func get_neptune_tags(svc *rds.RDS, reg prometheus.Registerer) error {
+	// Page through all of the clusters running the neptune engine
+	clusters := make([]*rds.DBCluster, 0)
+	input := &rds.DescribeDBClustersInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("engine"),
+				Values: []*string{aws.String("neptune")},
+			},
+		},
+	}
+	err := svc.DescribeDBClustersPages(input,
+		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.DBClusters...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the clusters, gather the tag names and add them to the tags map
+	// Keep the tags for each cluster so we only list them once
+	tags := make(map[string]string)
+	clusterTags := make(map[string][]*rds.Tag)
+	for _, f := range clusters {
+		// Create input for ListTagsForResource method
+		input := &rds.ListTagsForResourceInput{
+			ResourceName: f.DBClusterArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		clusterTags[*f.DBClusterArn] = resultTags.TagList
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.TagList {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each cluster and pupulate cluster map
+	cluster := make(map[string]map[string]string)
+	for _, f := range clusters {
+		// Initialize the map for this cluster
+		cluster[*f.DBClusterArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			cluster[*f.DBClusterArn][key] = ""
+		}
+
+		// Add metadata as tags
+		cluster[*f.DBClusterArn]["DBClusterIdentifier"] = aws.StringValue(f.DBClusterIdentifier)
+		cluster[*f.DBClusterArn]["Engine"] = aws.StringValue(f.Engine)
+		cluster[*f.DBClusterArn]["EngineVersion"] = aws.StringValue(f.EngineVersion)
+		cluster[*f.DBClusterArn]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the cluster's map with the tag values
+		for _, t := range clusterTags[*f.DBClusterArn] {
+			cluster[*f.DBClusterArn][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per cluster
+	neptune := new_collector_result(reg, "aws_neptune_cluster_tags", "Key:Value metric per Neptune cluster with all tags.", "DBClusterArn", cluster)
+	for key := range cluster {
+		neptune.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Network Firewall firewall, policy and rule group tags in us-west-2
+func get_network_firewall_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Network Firewall service client
+	svc := networkfirewall.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the firewalls
+	firewallList := make([]*networkfirewall.FirewallMetadata, 0)
+	err := svc.ListFirewallsPages(&networkfirewall.ListFirewallsInput{},
+		func(page *networkfirewall.ListFirewallsOutput, lastPage bool) bool {
+			firewallList = append(firewallList, page.Firewalls...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe every firewall, the list only returns names and ARNs
+	firewalls := make([]*networkfirewall.DescribeFirewallOutput, 0, len(firewallList))
+	for _, f := range firewallList {
+		result, err := svc.DescribeFirewall(&networkfirewall.DescribeFirewallInput{
+			FirewallArn: f.FirewallArn,
+		})
+		if err != nil {
+			return err
+		}
+		// Skip firewalls deleted since they were listed
+		if result.Firewall == nil {
+			continue
+		}
+		firewalls = append(firewalls, result)
+	}
+
+	// Iterate through all the firewalls, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range firewalls {
+		for _, v := range f.Firewall.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each firewall and pupulate firewall map
+	firewall := make(map[string]map[string]string)
+	for _, f := range firewalls {
+		// The status is missing while the firewall is being created
+		status := ""
+		if f.FirewallStatus != nil {
+			status = aws.StringValue(f.FirewallStatus.Status)
+		}
+
+		// Initialize the map for this firewall
+		firewall[*f.Firewall.FirewallArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			firewall[*f.Firewall.FirewallArn][key] = ""
+		}
+
+		// Add metadata as tags
+		firewall[*f.Firewall.FirewallArn]["FirewallName"] = aws.StringValue(f.Firewall.FirewallName)
+		firewall[*f.Firewall.FirewallArn]["VpcId"] = aws.StringValue(f.Firewall.VpcId)
+		firewall[*f.Firewall.FirewallArn]["FirewallStatus"] = status
+
+		// Populate the firewall's map with the tag values
+		for _, t := range f.Firewall.Tags {
+			firewall[*f.Firewall.FirewallArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per firewall
+	firewallGauge := new_collector_result(reg, "aws_network_firewall_tags", "Key:Value metric per Network Firewall firewall with all tags. 1 if READY, 0 otherwise.", "FirewallArn", firewall)
+	for key, value := range firewall {
+		if value["FirewallStatus"] == networkfirewall.FirewallStatusValueReady {
+			firewallGauge.Set(key, 1)
+		} else {
+			firewallGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the firewall policies
+	policyList := make([]*networkfirewall.FirewallPolicyMetadata, 0)
+	err = svc.ListFirewallPoliciesPages(&networkfirewall.ListFirewallPoliciesInput{},
+		func(page *networkfirewall.ListFirewallPoliciesOutput, lastPage bool) bool {
+			policyList = append(policyList, page.FirewallPolicies...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe every firewall policy, the list only returns names and ARNs
+	policies := make([]*networkfirewall.FirewallPolicyResponse, 0, len(policyList))
+	for _, f := range policyList {
+		result, err := svc.DescribeFirewallPolicy(&networkfirewall.DescribeFirewallPolicyInput{
+			FirewallPolicyArn: f.Arn,
+		})
+		if err != nil {
+			return err
+		}
+		policies = append(policies, result.FirewallPolicyResponse)
+	}
+
+	// Iterate through all the firewall policies, gather the tag names and add them to the policyTags map
+	policyTags := make(map[string]string)
+	for _, f := range policies {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := policyTags[*v.Key]; !ok {
+				policyTags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each firewall policy and pupulate firewall policy map
+	policy := make(map[string]map[string]string)
+	for _, f := range policies {
+		// Initialize the map for this firewall policy
+		policy[*f.FirewallPolicyArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range policyTags {
+			policy[*f.FirewallPolicyArn][key] = ""
+		}
+
+		// Add metadata as tags
+		policy[*f.FirewallPolicyArn]["FirewallPolicyName"] = aws.StringValue(f.FirewallPolicyName)
+		policy[*f.FirewallPolicyArn]["FirewallPolicyStatus"] = aws.StringValue(f.FirewallPolicyStatus)
+
+		// Populate the firewall policy's map with the tag values
+		for _, t := range f.Tags {
+			policy[*f.FirewallPolicyArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per firewall policy
+	policyGauge := new_collector_result(reg, "aws_network_firewall_policy_tags", "Key:Value metric per Network Firewall policy with all tags. 1 if ACTIVE, 0 otherwise.", "FirewallPolicyArn", policy)
+	for key, value := range policy {
+		if value["FirewallPolicyStatus"] == networkfirewall.ResourceStatusActive {
+			policyGauge.Set(key, 1)
+		} else {
+			policyGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the rule groups
+	ruleGroupList := make([]*networkfirewall.RuleGroupMetadata, 0)
+	err = svc.ListRuleGroupsPages(&networkfirewall.ListRuleGroupsInput{},
+		func(page *networkfirewall.ListRuleGroupsOutput, lastPage bool) bool {
+			ruleGroupList = append(ruleGroupList, page.RuleGroups...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe every rule group, the list only returns names and ARNs
+	ruleGroups := make([]*networkfirewall.RuleGroupResponse, 0, len(ruleGroupList))
+	for _, f := range ruleGroupList {
+		result, err := svc.DescribeRuleGroup(&networkfirewall.DescribeRuleGroupInput{
+			RuleGroupArn: f.Arn,
+		})
+		if err != nil {
+			return err
+		}
+		ruleGroups = append(ruleGroups, result.RuleGroupResponse)
+	}
+
+	// Iterate through all the rule groups, gather the tag names and add them to the ruleGroupTags map
+	ruleGroupTags := make(map[string]string)
+	for _, f := range ruleGroups {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := ruleGroupTags[*v.Key]; !ok {
+				ruleGroupTags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each rule group and pupulate rule group map
+	ruleGroup := make(map[string]map[string]string)
+	for _, f := range ruleGroups {
+		// Initialize the map for this rule group
+		ruleGroup[*f.RuleGroupArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range ruleGroupTags {
+			ruleGroup[*f.RuleGroupArn][key] = ""
+		}
+
+		// Add metadata as tags
+		ruleGroup[*f.RuleGroupArn]["RuleGroupName"] = aws.StringValue(f.RuleGroupName)
+		ruleGroup[*f.RuleGroupArn]["Type"] = aws.StringValue(f.Type)
+		ruleGroup[*f.RuleGroupArn]["RuleGroupStatus"] = aws.StringValue(f.RuleGroupStatus)
+
+		// Populate the rule group's map with the tag values
+		for _, t := range f.Tags {
+			ruleGroup[*f.RuleGroupArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per rule group
+	ruleGroupGauge := new_collector_result(reg, "aws_network_firewall_rule_group_tags", "Key:Value metric per Network Firewall rule group with all tags. 1 if ACTIVE, 0 otherwise.", "RuleGroupArn", ruleGroup)
+	for key, value := range ruleGroup {
+		if value["RuleGroupStatus"] == networkfirewall.ResourceStatusActive {
+			ruleGroupGauge.Set(key, 1)
+		} else {
+			ruleGroupGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists the port and availability zone of every IP target behind a Network Load Balancer in us-west-2
+func get_nlb_target_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create ELBv2 service client
+	svc := elbv2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the load balancers and keep the NLBs
+	nlbs := make(map[string]bool)
+	err := svc.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, f := range page.LoadBalancers {
+				if aws.StringValue(f.Type) == elbv2.LoadBalancerTypeEnumNetwork {
+					nlbs[*f.LoadBalancerArn] = true
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Page through all of the target groups and keep the IP target groups of an NLB
+	targetGroups := make([]*elbv2.TargetGroup, 0)
+	err = svc.DescribeTargetGroupsPages(&elbv2.DescribeTargetGroupsInput{},
+		func(page *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+			for _, f := range page.TargetGroups {
+				if aws.StringValue(f.TargetType) != elbv2.TargetTypeEnumIp {
+					continue
+				}
+				for _, arn := range f.LoadBalancerArns {
+					if nlbs[*arn] {
+						targetGroups = append(targetGroups, f)
+						break
+					}
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for the port of each target
+	targetPort := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_nlb_target_port",
+			Help: "Metric per NLB IP target and port. 1 if healthy, 0 otherwise.",
+		},
+		[]string{"TargetGroupArn", "TargetId", "Port", "HealthStatus"},
+	)
+	reg.MustRegister(targetPort)
+
+	// Create and register a new gauge for the availability zone of each target
+	targetAZ := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_nlb_target_az",
+			Help: "Availability zone of each NLB IP target.",
+		},
+		[]string{"TargetGroupArn", "TargetId", "Port", "AvailabilityZone"},
+	)
+	reg.MustRegister(targetAZ)
+
+	// Look up the health of the targets in each target group
+	for _, f := range targetGroups {
+		result, err := svc.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: f.TargetGroupArn,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, t := range result.TargetHealthDescriptions {
+			targetId := aws.StringValue(t.Target.Id)
+			port := strconv.FormatInt(aws.Int64Value(t.Target.Port), 10)
+			state := aws.StringValue(t.TargetHealth.State)
+			if state == elbv2.TargetHealthStateEnumHealthy {
+				targetPort.WithLabelValues(*f.TargetGroupArn, targetId, port, state).Set(1)
+			} else {
+				targetPort.WithLabelValues(*f.TargetGroupArn, targetId, port, state).Set(0)
+			}
+			targetAZ.WithLabelValues(*f.TargetGroupArn, targetId, port, aws.StringValue(t.Target.AvailabilityZone)).Set(1)
+		}
+	}
+	count_resources(reg, len(targetGroups))
+	return nil
+}
+
+// Lists all AWS Organizations service control policy tags and the organization roots
+func get_organizations_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Organizations service client, the global endpoint lives in us-east-1
+	svc := organizations.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// Page through all of the service control policies
+	policies := make([]*organizations.PolicySummary, 0)
+	err := svc.ListPoliciesPages(&organizations.ListPoliciesInput{
+		Filter: aws.String(organizations.PolicyTypeServiceControlPolicy),
+	},
+		func(page *organizations.ListPoliciesOutput, lastPage bool) bool {
+			policies = append(policies, page.Policies...)
+			return true
+		})
+	if err != nil {
+		// Accounts outside of an organization have no policies
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == organizations.ErrCodeAWSOrganizationsNotInUseException {
+			return nil
+		}
+		return err
+	}
+
+	// Iterate through all the policies, gather the tag names and add them to the tags map
+	// Keep the tags for each policy so we only list them once
+	tags := make(map[string]string)
+	policyTags := make(map[string][]*organizations.Tag)
+	for _, f := range policies {
+		err := svc.ListTagsForResourcePages(&organizations.ListTagsForResourceInput{
+			ResourceId: f.Id,
+		},
+			func(page *organizations.ListTagsForResourceOutput, lastPage bool) bool {
+				policyTags[*f.Id] = append(policyTags[*f.Id], page.Tags...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		// If the key is not in the map, add it
+		for _, v := range policyTags[*f.Id] {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each policy and pupulate policy map
+	policy := make(map[string]map[string]string)
+	for _, f := range policies {
+		// Initialize the map for this policy
+		policy[*f.Id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			policy[*f.Id][key] = ""
+		}
+
+		// Add metadata as tags
+		policy[*f.Id]["Arn"] = aws.StringValue(f.Arn)
+		policy[*f.Id]["Name"] = aws.StringValue(f.Name)
+		policy[*f.Id]["Description"] = aws.StringValue(f.Description)
+		policy[*f.Id]["AwsManaged"] = strconv.FormatBool(aws.BoolValue(f.AwsManaged))
+
+		// Populate the policy's map with the tag values
+		for _, t := range policyTags[*f.Id] {
+			policy[*f.Id][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, customer managed policies are 1 and AWS managed ones are 0
+	policyGauge := new_collector_result(reg, "aws_organizations_scp_tags", "Key:Value metric per AWS Organizations service control policy with all tags, 1 if the policy is customer managed.", "Id", policy)
+	for _, f := range policies {
+		if aws.BoolValue(f.AwsManaged) {
+			policyGauge.Set(*f.Id, 0)
+		} else {
+			policyGauge.Set(*f.Id, 1)
+		}
+	}
+
+	// Page through all of the roots
+	roots := make([]*organizations.Root, 0)
+	err = svc.ListRootsPages(&organizations.ListRootsInput{},
+		func(page *organizations.ListRootsOutput, lastPage bool) bool {
+			roots = append(roots, page.Roots...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new info gauge for each root
+	root := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_organizations_root_id",
+			Help: "Info metric per AWS Organizations root, always 1.",
+		},
+		[]string{"Id", "Arn", "Name"},
+	)
+	reg.MustRegister(root)
+	for _, f := range roots {
+		root.WithLabelValues(aws.StringValue(f.Id), aws.StringValue(f.Arn), aws.StringValue(f.Name)).Set(1)
+	}
+	return nil
+}
+
+// Lists all Outposts and Outposts site tags in us-west-2
+func get_outposts_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Outposts service client
+	svc := outposts.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the outposts
+	outpostList := make([]*outposts.Outpost, 0)
+	err := svc.ListOutpostsPages(&outposts.ListOutpostsInput{},
+		func(page *outposts.ListOutpostsOutput, lastPage bool) bool {
+			outpostList = append(outpostList, page.Outposts...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the outposts, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range outpostList {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each outpost and pupulate outpost map
+	outpost := make(map[string]map[string]string)
+	for _, f := range outpostList {
+		// Initialize the map for this outpost
+		outpost[*f.OutpostArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			outpost[*f.OutpostArn][key] = ""
+		}
+
+		// Add metadata as tags
+		outpost[*f.OutpostArn]["OutpostId"] = aws.StringValue(f.OutpostId)
+		outpost[*f.OutpostArn]["Name"] = aws.StringValue(f.Name)
+		outpost[*f.OutpostArn]["OwnerId"] = aws.StringValue(f.OwnerId)
+		outpost[*f.OutpostArn]["LifeCycleStatus"] = aws.StringValue(f.LifeCycleStatus)
+
+		// Populate the outpost's map with the tag values
+		for k, v := range f.Tags {
+			outpost[*f.OutpostArn][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per outpost
+	// The SDK has no constants for the life cycle status so it is compared to the API value
+	outpostGauge := new_collector_result(reg, "aws_outposts_tags", "Key:Value metric per Outpost with all tags. 1 if ACTIVE, 0 otherwise.", "OutpostArn", outpost)
+	for key, value := range outpost {
+		if value["LifeCycleStatus"] == "ACTIVE" {
+			outpostGauge.Set(key, 1)
+		} else {
+			outpostGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the sites
+	sites := make([]*outposts.Site, 0)
+	err = svc.ListSitesPages(&outposts.ListSitesInput{},
+		func(page *outposts.ListSitesOutput, lastPage bool) bool {
+			sites = append(sites, page.Sites...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the sites, gather the tag names and add them to the siteTags map
+	siteTags := make(map[string]string)
+	for _, f := range sites {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := siteTags[k]; !ok {
+				siteTags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each site and pupulate site map
+	site := make(map[string]map[string]string)
+	for _, f := range sites {
+		// Initialize the map for this site
+		site[*f.SiteId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range siteTags {
+			site[*f.SiteId][key] = ""
+		}
+
+		// Add metadata as tags
+		site[*f.SiteId]["Name"] = aws.StringValue(f.Name)
+
+		// Populate the site's map with the tag values
+		for k, v := range f.Tags {
+			site[*f.SiteId][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per site
+	siteGauge := new_collector_result(reg, "aws_outposts_site_tags", "Key:Value metric per Outposts site with all tags.", "SiteId", site)
+	for key := range site {
+		siteGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Pinpoint application tags and import job counts in us-west-2
+func get_pinpoint_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Pinpoint service client
+	svc := pinpoint.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the applications, the SDK has no paginator for GetApps
+	apps := make([]*pinpoint.ApplicationResponse, 0)
+	input := &pinpoint.GetAppsInput{}
+	for {
+		result, err := svc.GetApps(input)
+		if err != nil {
+			return err
+		}
+		apps = append(apps, result.ApplicationsResponse.Item...)
+		if aws.StringValue(result.ApplicationsResponse.NextToken) == "" {
+			break
+		}
+		input.Token = result.ApplicationsResponse.NextToken
+	}
+
+	// Iterate through all the applications, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range apps {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each application and pupulate application map
+	app := make(map[string]map[string]string)
+	for _, f := range apps {
+		// Initialize the map for this application
+		app[*f.Id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			app[*f.Id][key] = ""
+		}
+
+		// Add metadata as tags
+		app[*f.Id]["Name"] = aws.StringValue(f.Name)
+
+		// Populate the application's map with the tag values
+		for k, v := range f.Tags {
+			app[*f.Id][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per application
+	appGauge := new_collector_result(reg, "aws_pinpoint_app_tags", "Key:Value metric per Pinpoint application with all tags.", "ApplicationId", app)
+	for key := range app {
+		appGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the number of import jobs of each application
+	importJobCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_pinpoint_import_job_count",
+			Help: "Number of import jobs of each Pinpoint application.",
+		},
+		[]string{"ApplicationId"},
+	)
+	reg.MustRegister(importJobCount)
+
+	for _, f := range apps {
+		// Page through the import jobs of the application
+		count := 0
+		jobsInput := &pinpoint.GetImportJobsInput{
+			ApplicationId: f.Id,
+		}
+		for {
+			result, err := svc.GetImportJobs(jobsInput)
+			if err != nil {
+				return err
+			}
+			count += len(result.ImportJobsResponse.Item)
+			if aws.StringValue(result.ImportJobsResponse.NextToken) == "" {
+				break
+			}
+			jobsInput.Token = result.ImportJobsResponse.NextToken
+		}
+		importJobCount.WithLabelValues(aws.StringValue(f.Id)).Set(float64(count))
+	}
+	return nil
+}
+
+// Lists all EC2 placement group tags in us-west-2
+func get_placement_group_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// DescribePlacementGroups is not paginated, an account only has a few groups
+	result, err := svc.DescribePlacementGroups(nil)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the groups, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.PlacementGroups {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each group and pupulate group map
+	group := make(map[string]map[string]string)
+	for _, f := range result.PlacementGroups {
+		// Only partition groups have a partition count
+		partitionCount := ""
+		if f.PartitionCount != nil {
+			partitionCount = strconv.FormatInt(aws.Int64Value(f.PartitionCount), 10)
+		}
+
+		// Initialize the map for this group
+		group[*f.GroupId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			group[*f.GroupId][key] = ""
+		}
+
+		// Add metadata as tags
+		group[*f.GroupId]["GroupName"] = aws.StringValue(f.GroupName)
+		group[*f.GroupId]["Strategy"] = aws.StringValue(f.Strategy)
+		group[*f.GroupId]["State"] = aws.StringValue(f.State)
+		group[*f.GroupId]["PartitionCount"] = partitionCount
+
+		// Populate the group's map with the tag values
+		for _, t := range f.Tags {
+			group[*f.GroupId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, available groups are 1 and pending or deleting ones are 0
+	groupGauge := new_collector_result(reg, "aws_placement_group_tags", "Key:Value metric per EC2 placement group with all tags, 1 if the group is available.", "GroupId", group)
+	for _, f := range result.PlacementGroups {
+		if aws.StringValue(f.State) == ec2.PlacementGroupStateAvailable {
+			groupGauge.Set(*f.GroupId, 1)
+		} else {
+			groupGauge.Set(*f.GroupId, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all RAM resource share tags in us-west-2, both shares owned by the account and shares received from other accounts
+func get_ram_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create RAM service client
+	svc := ram.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the resource shares of each owner
+	shares := make([]*ram.ResourceShare, 0)
+	for _, owner := range []string{ram.ResourceOwnerSelf, ram.ResourceOwnerOtherAccounts} {
+		err := svc.GetResourceSharesPages(&ram.GetResourceSharesInput{
+			ResourceOwner: aws.String(owner),
+		},
+			func(page *ram.GetResourceSharesOutput, lastPage bool) bool {
+				shares = append(shares, page.ResourceShares...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate through all the resource shares, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range shares {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each resource share and pupulate resource share map
+	share := make(map[string]map[string]string)
+	for _, f := range shares {
+		// Initialize the map for this resource share
+		share[*f.ResourceShareArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			share[*f.ResourceShareArn][key] = ""
+		}
+
+		// Add metadata as tags
+		share[*f.ResourceShareArn]["Name"] = aws.StringValue(f.Name)
+		share[*f.ResourceShareArn]["Status"] = aws.StringValue(f.Status)
+		share[*f.ResourceShareArn]["OwningAccountId"] = aws.StringValue(f.OwningAccountId)
+		share[*f.ResourceShareArn]["FeatureSet"] = aws.StringValue(f.FeatureSet)
+
+		// Populate the resource share's map with the tag values
+		for _, t := range f.Tags {
+			share[*f.ResourceShareArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per resource share
+	shareGauge := new_collector_result(reg, "aws_ram_resource_share_tags", "Key:Value metric per RAM resource share with all tags. 1 if ACTIVE, 0 otherwise.", "ResourceShareArn", share)
+	for key, value := range share {
+		if value["Status"] == ram.ResourceShareStatusActive {
+			shareGauge.Set(key, 1)
+		} else {
+			shareGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Create an RDS service client in us-west-2
+// RDS, Neptune and DocumentDB are all served by the RDS API and share this client
+func get_rds_client(sess *session.Session, region string) *rds.RDS {
+	// Create RDS service client
+	return rds.New(sess, &aws.Config{Region: aws.String(region)})
+}
+
+// Lists all RDS tags in us-west-2
+func get_rds_tags(svc *rds.RDS, reg prometheus.Registerer) error {
+	result, err := svc.DescribeDBInstances(nil)
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the dBInstances, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range result.DBInstances {
+		// Create input for ListTagsForResource method
+		resourceName := aws.StringValue(f.DBInstanceArn)
+		input := &rds.ListTagsForResourceInput{
+			ResourceName: aws.String(resourceName),
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.TagList {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each dbInstance and pupulate dbInstance map
+	dbInstance := make(map[string]map[string]string)
+	for _, f := range result.DBInstances {
+		// Create input for ListTagsForResource method
+		resourceName := aws.StringValue(f.DBInstanceArn)
+		input := &rds.ListTagsForResourceInput{
+			ResourceName: aws.String(resourceName),
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+
+		// Initialize the map for this dbInstance
+		dbInstance[*f.DBInstanceArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			dbInstance[*f.DBInstanceArn][key] = ""
+		}
+
+		// Add metadata as tags
+		dbInstance[*f.DBInstanceArn]["DBName"] = aws.StringValue(f.DBName)
+		dbInstance[*f.DBInstanceArn]["DBInstanceIdentifier"] = aws.StringValue(f.DBInstanceIdentifier)
+
+		// Populate the dbInstance's map with the tag values
+		for _, t := range resultTags.TagList {
+			dbInstance[*f.DBInstanceArn][*t.Key] = *t.Value
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per dbInstance
+	rds := new_collector_result(reg, "aws_rds_tags", "Key:Value metric per RDS instance with all tags.", "DBInstanceArn", dbInstance)
+	for key := range dbInstance {
+		rds.Set(key, 1)
+	}
+
+	// Snapshots are collected with the same client
+	return get_rds_snapshot_tags(svc, reg)
+}
+
+// Lists all manual RDS snapshot tags with the client of the RDS collector
+func get_rds_snapshot_tags(svc *rds.RDS, reg prometheus.Registerer) error {
+	// Page through all of the manual snapshots, automated ones come and go with the retention period
+	snapshots := make([]*rds.DBSnapshot, 0)
+	err := svc.DescribeDBSnapshotsPages(&rds.DescribeDBSnapshotsInput{
+		SnapshotType: aws.String("manual"),
+	},
+		func(page *rds.DescribeDBSnapshotsOutput, lastPage bool) bool {
+			snapshots = append(snapshots, page.DBSnapshots...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the snapshots, gather the tag names and add them to the tags map
+	// Keep the tags for each snapshot so we only list them once
+	tags := make(map[string]string)
+	snapshotTags := make(map[string][]*rds.Tag)
+	for _, f := range snapshots {
+		// Create input for ListTagsForResource method
+		input := &rds.ListTagsForResourceInput{
+			ResourceName: f.DBSnapshotArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		snapshotTags[*f.DBSnapshotArn] = resultTags.TagList
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.TagList {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each snapshot and pupulate snapshot map
+	snapshot := make(map[string]map[string]string)
+	for _, f := range snapshots {
+		// Snapshots still being created have no create time yet
+		createTime := ""
+		if f.SnapshotCreateTime != nil {
+			createTime = strconv.FormatInt(f.SnapshotCreateTime.Unix(), 10)
+		}
+
+		// Initialize the map for this snapshot
+		snapshot[*f.DBSnapshotArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			snapshot[*f.DBSnapshotArn][key] = ""
+		}
+
+		// Add metadata as tags
+		snapshot[*f.DBSnapshotArn]["DBSnapshotIdentifier"] = aws.StringValue(f.DBSnapshotIdentifier)
+		snapshot[*f.DBSnapshotArn]["DBInstanceIdentifier"] = aws.StringValue(f.DBInstanceIdentifier)
+		snapshot[*f.DBSnapshotArn]["SnapshotCreateTime"] = createTime
+		snapshot[*f.DBSnapshotArn]["Status"] = aws.StringValue(f.Status)
+
+		// Populate the snapshot's map with the tag values
+		for _, t := range snapshotTags[*f.DBSnapshotArn] {
+			snapshot[*f.DBSnapshotArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per snapshot
+	snapshotGauge := new_collector_result(reg, "aws_rds_snapshot_tags", "Key:Value metric per manual RDS snapshot with all tags.", "DBSnapshotArn", snapshot)
+	for key := range snapshot {
+		snapshotGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the size of each snapshot
+	size := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_rds_snapshot_size_bytes",
+			Help: "Allocated storage of the manual RDS snapshot in bytes.",
+		},
+		[]string{"DBSnapshotIdentifier", "DBSnapshotArn"},
+	)
+	reg.MustRegister(size)
+	for _, f := range snapshots {
+		// The allocated storage is given in GiB
+		size.WithLabelValues(aws.StringValue(f.DBSnapshotIdentifier), aws.StringValue(f.DBSnapshotArn)).Set(float64(aws.Int64Value(f.AllocatedStorage) * 1073741824))
+	}
+	return nil
+}
+
+// Lists all Rekognition collection tags and face counts in us-west-2
+func get_rekognition_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Rekognition service client
+	svc := rekognition.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the collection ids
+	collectionIds := make([]*string, 0)
+	err := svc.ListCollectionsPages(&rekognition.ListCollectionsInput{},
+		func(page *rekognition.ListCollectionsOutput, lastPage bool) bool {
+			collectionIds = append(collectionIds, page.CollectionIds...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for the number of faces in each collection
+	faceCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_rekognition_collection_face_count",
+			Help: "Number of faces indexed into each Rekognition collection.",
+		},
+		[]string{"CollectionId"},
+	)
+	reg.MustRegister(faceCount)
+
+	// Iterate through all the collections, gather the tag names and add them to the tags map
+	// Keep the tags for each collection so we only list them once
+	tags := make(map[string]string)
+	collectionTags := make(map[string]map[string]*string)
+	collectionArns := make(map[string]string)
+	for _, id := range collectionIds {
+		// Describe the collection for its ARN and face count
+		result, err := svc.DescribeCollection(&rekognition.DescribeCollectionInput{
+			CollectionId: id,
+		})
+		if err != nil {
+			return err
+		}
+		collectionArns[*id] = aws.StringValue(result.CollectionARN)
+		faceCount.WithLabelValues(*id).Set(float64(aws.Int64Value(result.FaceCount)))
+
+		// Create input for ListTagsForResource method
+		input := &rekognition.ListTagsForResourceInput{
+			ResourceArn: result.CollectionARN,
+		}
+
+		// List out the tags
+		var resultTags *rekognition.ListTagsForResourceOutput
+		resultTags, err = svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		collectionTags[*id] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for k, _ := range resultTags.Tags {
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each collection and pupulate collection map
+	collection := make(map[string]map[string]string)
+	for _, id := range collectionIds {
+		// Initialize the map for this collection
+		collection[*id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			collection[*id][key] = ""
+		}
+
+		// Add metadata as tags
+		collection[*id]["CollectionArn"] = collectionArns[*id]
+
+		// Populate the collection's map with the tag values
+		for k, v := range collectionTags[*id] {
+			collection[*id][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per collection
+	collectionGauge := new_collector_result(reg, "aws_rekognition_collection_tags", "Key:Value metric per Rekognition collection with all tags.", "CollectionId", collection)
+	for key := range collection {
+		collectionGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Report the active EC2 Reserved Instances in us-west-2 and when they expire
+func get_reserved_instance_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// DescribeReservedInstances is not paginated, only the active reservations are listed
+	result, err := svc.DescribeReservedInstances(&ec2.DescribeReservedInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.ReservedInstanceStateActive}),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create and register new gauges for the instance count and expiry of each reservation
+	count := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_reserved_instance_count",
+			Help: "Number of instances covered by the active EC2 Reserved Instance.",
+		},
+		[]string{"ReservedInstancesId", "InstanceType", "AvailabilityZone", "Scope", "OfferingClass", "ProductDescription"},
+	)
+	expiry := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_reserved_instance_expiry_seconds",
+			Help: "Unix timestamp at which the active EC2 Reserved Instance expires.",
+		},
+		[]string{"ReservedInstancesId"},
+	)
+	reg.MustRegister(count, expiry)
+
+	for _, f := range result.ReservedInstances {
+		count.WithLabelValues(aws.StringValue(f.ReservedInstancesId), aws.StringValue(f.InstanceType), aws.StringValue(f.AvailabilityZone), aws.StringValue(f.Scope), aws.StringValue(f.OfferingClass), aws.StringValue(f.ProductDescription)).Set(float64(aws.Int64Value(f.InstanceCount)))
+		if f.End != nil {
+			expiry.WithLabelValues(aws.StringValue(f.ReservedInstancesId)).Set(float64(f.End.Unix()))
+		}
+	}
+	return nil
+}
+
+// Lists all SageMaker endpoint and notebook instance tags in us-west-2
+func get_sagemaker_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create SageMaker service client
+	svc := sagemaker.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the endpoints
+	endpoints := make([]*sagemaker.EndpointSummary, 0)
+	err := svc.ListEndpointsPages(&sagemaker.ListEndpointsInput{},
+		func(page *sagemaker.ListEndpointsOutput, lastPage bool) bool {
+			endpoints = append(endpoints, page.Endpoints...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the endpoints, gather the tag names and add them to the tags map
+	// Keep the tags for each endpoint so we only list them once
+	tags := make(map[string]string)
+	endpointTags := make(map[string][]*sagemaker.Tag)
+	for _, f := range endpoints {
+		// Create input for ListTags method
+		input := &sagemaker.ListTagsInput{
+			ResourceArn: f.EndpointArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTags(input)
+		if err != nil {
+			return err
+		}
+		endpointTags[*f.EndpointArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each endpoint and pupulate endpoint map
+	endpoint := make(map[string]map[string]string)
+	for _, f := range endpoints {
+		// Initialize the map for this endpoint
+		endpoint[*f.EndpointArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			endpoint[*f.EndpointArn][key] = ""
+		}
+
+		// Add metadata as tags
+		endpoint[*f.EndpointArn]["EndpointName"] = aws.StringValue(f.EndpointName)
+		endpoint[*f.EndpointArn]["EndpointStatus"] = aws.StringValue(f.EndpointStatus)
+
+		// Populate the endpoint's map with the tag values
+		for _, t := range endpointTags[*f.EndpointArn] {
+			endpoint[*f.EndpointArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per endpoint
+	endpointGauge := new_collector_result(reg, "aws_sagemaker_endpoint_tags", "Key:Value metric per SageMaker endpoint with all tags. 1 if InService, 0 otherwise.", "EndpointArn", endpoint)
+	for key, value := range endpoint {
+		if value["EndpointStatus"] == sagemaker.EndpointStatusInService {
+			endpointGauge.Set(key, 1)
+		} else {
+			endpointGauge.Set(key, 0)
+		}
+	}
+
+	// Page through all of the notebook instances
+	notebookInstances := make([]*sagemaker.NotebookInstanceSummary, 0)
+	err = svc.ListNotebookInstancesPages(&sagemaker.ListNotebookInstancesInput{},
+		func(page *sagemaker.ListNotebookInstancesOutput, lastPage bool) bool {
+			notebookInstances = append(notebookInstances, page.NotebookInstances...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the notebook instances, gather the tag names and add them to the notebookTagKeys map
+	// Keep the tags for each notebook instance so we only list them once
+	notebookTagKeys := make(map[string]string)
+	notebookTags := make(map[string][]*sagemaker.Tag)
+	for _, f := range notebookInstances {
+		// Create input for ListTags method
+		input := &sagemaker.ListTagsInput{
+			ResourceArn: f.NotebookInstanceArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTags(input)
+		if err != nil {
+			return err
+		}
+		notebookTags[*f.NotebookInstanceArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := notebookTagKeys[*v.Key]; !ok {
+				notebookTagKeys[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each notebook instance and pupulate notebook instance map
+	notebookInstance := make(map[string]map[string]string)
+	for _, f := range notebookInstances {
+		// Initialize the map for this notebook instance
+		notebookInstance[*f.NotebookInstanceArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range notebookTagKeys {
+			notebookInstance[*f.NotebookInstanceArn][key] = ""
+		}
+
+		// Add metadata as tags
+		notebookInstance[*f.NotebookInstanceArn]["NotebookInstanceName"] = aws.StringValue(f.NotebookInstanceName)
+		notebookInstance[*f.NotebookInstanceArn]["NotebookInstanceStatus"] = aws.StringValue(f.NotebookInstanceStatus)
+		notebookInstance[*f.NotebookInstanceArn]["InstanceType"] = aws.StringValue(f.InstanceType)
+
+		// Populate the notebook instance's map with the tag values
+		for _, t := range notebookTags[*f.NotebookInstanceArn] {
+			notebookInstance[*f.NotebookInstanceArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per notebook instance
+	notebookGauge := new_collector_result(reg, "aws_sagemaker_notebook_tags", "Key:Value metric per SageMaker notebook instance with all tags. 1 if InService, 0 otherwise.", "NotebookInstanceArn", notebookInstance)
+	for key, value := range notebookInstance {
+		if value["NotebookInstanceStatus"] == sagemaker.NotebookInstanceStatusInService {
+			notebookGauge.Set(key, 1)
+		} else {
+			notebookGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Savings Plan tags and when each plan expires
+func get_savings_plan_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Savings Plans service client, the global endpoint lives in us-east-1
+	svc := savingsplans.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// Page through all of the savings plans, their tags are part of the response
+	plans := make([]*savingsplans.SavingsPlan, 0)
+	input := &savingsplans.DescribeSavingsPlansInput{}
+	for {
+		result, err := svc.DescribeSavingsPlans(input)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, result.SavingsPlans...)
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	// Iterate through all the plans, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range plans {
+		for k, _ := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[k]; !ok {
+				tags[k] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each plan and pupulate plan map
+	plan := make(map[string]map[string]string)
+	for _, f := range plans {
+		// Initialize the map for this plan
+		plan[*f.SavingsPlanId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			plan[*f.SavingsPlanId][key] = ""
+		}
+
+		// Add metadata as tags
+		plan[*f.SavingsPlanId]["SavingsPlanArn"] = aws.StringValue(f.SavingsPlanArn)
+		plan[*f.SavingsPlanId]["SavingsPlanType"] = aws.StringValue(f.SavingsPlanType)
+		plan[*f.SavingsPlanId]["PaymentOption"] = aws.StringValue(f.PaymentOption)
+		plan[*f.SavingsPlanId]["Status"] = aws.StringValue(f.State)
+
+		// Populate the plan's map with the tag values
+		for k, v := range f.Tags {
+			plan[*f.SavingsPlanId][k] = aws.StringValue(v)
+		}
+	}
+
+	// Register a gauge labelled with every tag, active plans are 1 and all others are 0
+	planGauge := new_collector_result(reg, "aws_savings_plan_tags", "Key:Value metric per Savings Plan with all tags, 1 if the plan is active.", "SavingsPlanId", plan)
+	for _, f := range plans {
+		if aws.StringValue(f.State) == savingsplans.SavingsPlanStateActive {
+			planGauge.Set(*f.SavingsPlanId, 1)
+		} else {
+			planGauge.Set(*f.SavingsPlanId, 0)
+		}
+	}
+
+	// Create and register a new gauge for the end of each plan
+	expiry := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_savings_plan_expiry_seconds",
+			Help: "Unix timestamp at which the Savings Plan term ends.",
+		},
+		[]string{"SavingsPlanId"},
+	)
+	reg.MustRegister(expiry)
+	for _, f := range plans {
+		// The end of the term is sent as an ISO 8601 string, queued plans have none yet
+		if aws.StringValue(f.End) == "" {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, *f.End)
+		if err != nil {
+			return err
+		}
+		expiry.WithLabelValues(aws.StringValue(f.SavingsPlanId)).Set(float64(end.Unix()))
+	}
+	return nil
+}
+
+// Lists all Security Group tags in us-west-2
+func get_security_group_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the security groups
+	securityGroups := make([]*ec2.SecurityGroup, 0)
+	err := svc.DescribeSecurityGroupsPages(&ec2.DescribeSecurityGroupsInput{},
+		func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+			securityGroups = append(securityGroups, page.SecurityGroups...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the security groups, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range securityGroups {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each security group and pupulate security group map
+	securityGroup := make(map[string]map[string]string)
+	for _, f := range securityGroups {
+		// Initialize the map for this security group
+		securityGroup[*f.GroupId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			securityGroup[*f.GroupId][key] = ""
+		}
+
+		// Add metadata as tags
+		securityGroup[*f.GroupId]["GroupName"] = aws.StringValue(f.GroupName)
+		securityGroup[*f.GroupId]["VpcId"] = aws.StringValue(f.VpcId)
+
+		// Populate the security group's map with the tag values
+		for _, t := range f.Tags {
+			securityGroup[*f.GroupId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per security group
+	securityGroupGauge := new_collector_result(reg, "aws_security_group_tags", "Key:Value metric per Security Group with all tags.", "GroupId", securityGroup)
+	for key := range securityGroup {
+		securityGroupGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Count the new active Security Hub findings in us-west-2 and report the status of the enabled standards
+func get_securityhub_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Security Hub service client
+	svc := securityhub.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Create and register a new gauge for the number of findings
+	findings := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_securityhub_finding_count",
+			Help: "Number of new active Security Hub findings per severity, compliance status and product.",
+		},
+		[]string{"Severity", "ComplianceStatus", "ProductName"},
+	)
+	reg.MustRegister(findings)
+
+	// Page through all of the new active findings and count them
+	input := &securityhub.GetFindingsInput{
+		Filters: &securityhub.AwsSecurityFindingFilters{
+			RecordState: []*securityhub.StringFilter{
+				{
+					Comparison: aws.String(securityhub.StringFilterComparisonEquals),
+					Value:      aws.String(securityhub.RecordStateActive),
+				},
+			},
+			WorkflowStatus: []*securityhub.StringFilter{
+				{
+					Comparison: aws.String(securityhub.StringFilterComparisonEquals),
+					Value:      aws.String(securityhub.WorkflowStatusNew),
+				},
+			},
+		},
+	}
+	err := svc.GetFindingsPages(input,
+		func(page *securityhub.GetFindingsOutput, lastPage bool) bool {
+			for _, f := range page.Findings {
+				severity := ""
+				if f.Severity != nil {
+					severity = aws.StringValue(f.Severity.Label)
+				}
+
+				// Only findings of compliance checks have a compliance status
+				complianceStatus := ""
+				if f.Compliance != nil {
+					complianceStatus = aws.StringValue(f.Compliance.Status)
+				}
+				findings.WithLabelValues(severity, complianceStatus, aws.StringValue(f.ProductName)).Inc()
+			}
+			return true
+		})
+	if err != nil {
+		// Every Security Hub call fails when Security Hub is not enabled in the region
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == securityhub.ErrCodeInvalidAccessException {
+			log.Printf("WARNING: Security Hub is not enabled in %s, skipping", region)
+			return nil
+		}
+		return err
+	}
+
+	// Page through all of the enabled standards
+	standards := make([]*securityhub.StandardsSubscription, 0)
+	err = svc.GetEnabledStandardsPages(&securityhub.GetEnabledStandardsInput{},
+		func(page *securityhub.GetEnabledStandardsOutput, lastPage bool) bool {
+			standards = append(standards, page.StandardsSubscriptions...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for the status of each standards subscription
+	subscription := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_securityhub_standards_subscription_status",
+			Help: "1 if the Security Hub standards subscription is ready, 0 otherwise.",
+		},
+		[]string{"StandardsArn", "StandardsSubscriptionArn", "StandardsStatus"},
+	)
+	reg.MustRegister(subscription)
+	for _, f := range standards {
+		if aws.StringValue(f.StandardsStatus) == securityhub.StandardsStatusReady {
+			subscription.WithLabelValues(aws.StringValue(f.StandardsArn), aws.StringValue(f.StandardsSubscriptionArn), aws.StringValue(f.StandardsStatus)).Set(1)
+		} else {
+			subscription.WithLabelValues(aws.StringValue(f.StandardsArn), aws.StringValue(f.StandardsSubscriptionArn), aws.StringValue(f.StandardsStatus)).Set(0)
+		}
+	}
+	count_resources(reg, len(standards))
+	return nil
+}
+
+// Lists all Service Catalog portfolio and product tags in us-west-2
+// Service Catalog has no ListTagsForResource, the tags are returned by DescribePortfolio and DescribeProductAsAdmin
+func get_servicecatalog_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Service Catalog service client
+	svc := servicecatalog.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the portfolios
+	portfolios := make([]*servicecatalog.PortfolioDetail, 0)
+	err := svc.ListPortfoliosPages(&servicecatalog.ListPortfoliosInput{},
+		func(page *servicecatalog.ListPortfoliosOutput, lastPage bool) bool {
+			portfolios = append(portfolios, page.PortfolioDetails...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the portfolios, gather the tag names and add them to the tags map
+	// Keep the tags for each portfolio so we only describe them once
+	tags := make(map[string]string)
+	portfolioTags := make(map[string][]*servicecatalog.Tag)
+	for _, f := range portfolios {
+		// Create input for DescribePortfolio method
+		input := &servicecatalog.DescribePortfolioInput{
+			Id: f.Id,
+		}
+
+		// Describe the portfolio to get the tags
+		result, err := svc.DescribePortfolio(input)
+		if err != nil {
+			return err
+		}
+		portfolioTags[*f.Id] = result.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range result.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each portfolio and pupulate portfolio map
+	portfolio := make(map[string]map[string]string)
+	for _, f := range portfolios {
+		// Initialize the map for this portfolio
+		portfolio[*f.Id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			portfolio[*f.Id][key] = ""
+		}
+
+		// Add metadata as tags
+		portfolio[*f.Id]["DisplayName"] = aws.StringValue(f.DisplayName)
+		portfolio[*f.Id]["ProviderName"] = aws.StringValue(f.ProviderName)
+
+		// Populate the portfolio's map with the tag values
+		for _, t := range portfolioTags[*f.Id] {
+			portfolio[*f.Id][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per portfolio
+	portfolioGauge := new_collector_result(reg, "aws_servicecatalog_portfolio_tags", "Key:Value metric per Service Catalog portfolio with all tags.", "Id", portfolio)
+	for key := range portfolio {
+		portfolioGauge.Set(key, 1)
+	}
+
+	// Page through all of the products the account administers
+	products := make([]*servicecatalog.ProductViewDetail, 0)
+	err = svc.SearchProductsAsAdminPages(&servicecatalog.SearchProductsAsAdminInput{},
+		func(page *servicecatalog.SearchProductsAsAdminOutput, lastPage bool) bool {
+			products = append(products, page.ProductViewDetails...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the products, gather the tag names and add them to the productTagNames map
+	// Keep the tags for each product so we only describe them once
+	productTagNames := make(map[string]string)
+	productTags := make(map[string][]*servicecatalog.Tag)
+	for _, f := range products {
+		if f.ProductViewSummary == nil {
+			continue
+		}
+
+		// Create input for DescribeProductAsAdmin method
+		input := &servicecatalog.DescribeProductAsAdminInput{
+			Id: f.ProductViewSummary.ProductId,
+		}
+
+		// Describe the product to get the tags
+		result, err := svc.DescribeProductAsAdmin(input)
+		if err != nil {
+			return err
+		}
+		productTags[*f.ProductViewSummary.ProductId] = result.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range result.Tags {
+			if _, ok := productTagNames[*v.Key]; !ok {
+				productTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each product and pupulate product map
+	product := make(map[string]map[string]string)
+	for _, f := range products {
+		if f.ProductViewSummary == nil {
+			continue
+		}
+		id := *f.ProductViewSummary.ProductId
+
+		// Initialize the map for this product
+		product[id] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range productTagNames {
+			product[id][key] = ""
+		}
+
+		// Add metadata as tags
+		product[id]["Name"] = aws.StringValue(f.ProductViewSummary.Name)
+		product[id]["Type"] = aws.StringValue(f.ProductViewSummary.Type)
+		product[id]["Owner"] = aws.StringValue(f.ProductViewSummary.Owner)
+
+		// Populate the product's map with the tag values
+		for _, t := range productTags[id] {
+			product[id][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per product
+	productGauge := new_collector_result(reg, "aws_servicecatalog_product_tags", "Key:Value metric per Service Catalog product with all tags.", "ProductId", product)
+	for key := range product {
+		productGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all Shield Advanced protection tags and ongoing attacks, Shield is a global service
+func get_shield_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Shield service client, the global endpoint lives in us-east-1
+	svc := shield.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// Page through all of the protections
+	protections := make([]*shield.Protection, 0)
+	err := svc.ListProtectionsPages(&shield.ListProtectionsInput{},
+		func(page *shield.ListProtectionsOutput, lastPage bool) bool {
+			protections = append(protections, page.Protections...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the protections, gather the tag names and add them to the tags map
+	// Keep the tags for each protection so we only list them once
+	tags := make(map[string]string)
+	protectionTags := make(map[string][]*shield.Tag)
+	for _, f := range protections {
+		// Create input for ListTagsForResource method
+		input := &shield.ListTagsForResourceInput{
+			ResourceARN: f.ProtectionArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		protectionTags[*f.ProtectionArn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each protection and pupulate protection map
+	protection := make(map[string]map[string]string)
+	for _, f := range protections {
+		// Initialize the map for this protection
+		protection[*f.ProtectionArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			protection[*f.ProtectionArn][key] = ""
+		}
+
+		// Add metadata as tags
+		protection[*f.ProtectionArn]["Id"] = aws.StringValue(f.Id)
+		protection[*f.ProtectionArn]["Name"] = aws.StringValue(f.Name)
+		protection[*f.ProtectionArn]["ResourceArn"] = aws.StringValue(f.ResourceArn)
+
+		// Populate the protection's map with the tag values
+		for _, t := range protectionTags[*f.ProtectionArn] {
+			protection[*f.ProtectionArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per protection
+	protectionGauge := new_collector_result(reg, "aws_shield_protection_tags", "Key:Value metric per Shield Advanced protection with all tags.", "ProtectionArn", protection)
+	for key := range protection {
+		protectionGauge.Set(key, 1)
+	}
+
+	// Page through all of the attacks which started in the last 7 days
+	attacks := make([]*shield.AttackSummary, 0)
+	err = svc.ListAttacksPages(&shield.ListAttacksInput{
+		StartTime: &shield.TimeRange{
+			FromInclusive: aws.Time(time.Now().AddDate(0, 0, -7)),
+		},
+	},
+		func(page *shield.ListAttacksOutput, lastPage bool) bool {
+			attacks = append(attacks, page.AttackSummaries...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Create and register a new gauge for the number of ongoing attacks, an attack without an end time is still ongoing
+	activeAttacks := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aws_shield_active_attack_count",
+			Help: "Number of ongoing attacks on resources protected by Shield Advanced.",
+		},
+	)
+	reg.MustRegister(activeAttacks)
+	count := 0
+	for _, f := range attacks {
+		if f.EndTime == nil {
+			count++
+		}
+	}
+	activeAttacks.Set(float64(count))
+	return nil
+}
+
+// Lists all active EC2 Spot Instance request tags in us-west-2
+func get_spot_request_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the active spot requests, their tags are part of the response
+	requests := make([]*ec2.SpotInstanceRequest, 0)
+	err := svc.DescribeSpotInstanceRequestsPages(&ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.SpotInstanceStateActive}),
+			},
+		},
+	},
+		func(page *ec2.DescribeSpotInstanceRequestsOutput, lastPage bool) bool {
+			requests = append(requests, page.SpotInstanceRequests...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the requests, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range requests {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each request and pupulate request map
+	request := make(map[string]map[string]string)
+	for _, f := range requests {
+		// The status is optional, default it to empty
+		status := ""
+		if f.Status != nil {
+			status = aws.StringValue(f.Status.Code)
+		}
+
+		// Initialize the map for this request
+		request[*f.SpotInstanceRequestId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			request[*f.SpotInstanceRequestId][key] = ""
+		}
+
+		// Add metadata as tags
+		request[*f.SpotInstanceRequestId]["InstanceId"] = aws.StringValue(f.InstanceId)
+		request[*f.SpotInstanceRequestId]["SpotPrice"] = aws.StringValue(f.SpotPrice)
+		request[*f.SpotInstanceRequestId]["Type"] = aws.StringValue(f.Type)
+		request[*f.SpotInstanceRequestId]["State"] = aws.StringValue(f.State)
+		request[*f.SpotInstanceRequestId]["Status"] = status
+
+		// Populate the request's map with the tag values
+		for _, t := range f.Tags {
+			request[*f.SpotInstanceRequestId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per request
+	requestGauge := new_collector_result(reg, "aws_spot_request_tags", "Key:Value metric per active EC2 Spot Instance request with all tags.", "SpotInstanceRequestId", request)
+	for key := range request {
+		requestGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the fulfillment of each request
+	fulfilled := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_spot_request_fulfilled",
+			Help: "1 if the EC2 Spot Instance request is fulfilled, 0 otherwise.",
+		},
+		[]string{"SpotInstanceRequestId"},
+	)
+	reg.MustRegister(fulfilled)
+	for _, f := range requests {
+		// The status codes have no SDK enum, fulfilled means the instance is running
+		if f.Status != nil && aws.StringValue(f.Status.Code) == "fulfilled" {
+			fulfilled.WithLabelValues(aws.StringValue(f.SpotInstanceRequestId)).Set(1)
+		} else {
+			fulfilled.WithLabelValues(aws.StringValue(f.SpotInstanceRequestId)).Set(0)
+		}
+	}
+	return nil
+}
+
+// Lists all Systems Manager maintenance window tags in us-west-2
+func get_ssm_maintenance_window_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create SSM service client
+	svc := ssm.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the maintenance windows
+	windows := make([]*ssm.MaintenanceWindowIdentity, 0)
+	err := svc.DescribeMaintenanceWindowsPages(&ssm.DescribeMaintenanceWindowsInput{},
+		func(page *ssm.DescribeMaintenanceWindowsOutput, lastPage bool) bool {
+			windows = append(windows, page.WindowIdentities...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the windows, gather the tag names and add them to the tags map
+	// Keep the tags for each window so we only list them once
+	tags := make(map[string]string)
+	windowTags := make(map[string][]*ssm.Tag)
+	for _, f := range windows {
+		// Create input for ListTagsForResource method
+		input := &ssm.ListTagsForResourceInput{
+			ResourceId:   f.WindowId,
+			ResourceType: aws.String(ssm.ResourceTypeForTaggingMaintenanceWindow),
 		}
 
 		// List out the tags
-		resultTags, err := svc.DescribeTags(input)
+		resultTags, err := svc.ListTagsForResource(input)
 		if err != nil {
-			fmt.Println(err.Error())
-			return
+			return err
+		}
+		windowTags[*f.WindowId] = resultTags.TagList
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.TagList {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each window and pupulate window map
+	window := make(map[string]map[string]string)
+	for _, f := range windows {
+		// Initialize the map for this window
+		window[*f.WindowId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			window[*f.WindowId][key] = ""
 		}
 
+		// Add metadata as tags
+		window[*f.WindowId]["Name"] = aws.StringValue(f.Name)
+		window[*f.WindowId]["Enabled"] = strconv.FormatBool(aws.BoolValue(f.Enabled))
+		window[*f.WindowId]["Schedule"] = aws.StringValue(f.Schedule)
+		window[*f.WindowId]["Duration"] = strconv.FormatInt(aws.Int64Value(f.Duration), 10)
+
+		// Populate the window's map with the tag values
+		for _, t := range windowTags[*f.WindowId] {
+			window[*f.WindowId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag, enabled windows are 1 and disabled ones are 0
+	windowGauge := new_collector_result(reg, "aws_ssm_maintenance_window_tags", "Key:Value metric per Systems Manager maintenance window with all tags, 1 if the window is enabled.", "WindowId", window)
+	for _, f := range windows {
+		if aws.BoolValue(f.Enabled) {
+			windowGauge.Set(*f.WindowId, 1)
+		} else {
+			windowGauge.Set(*f.WindowId, 0)
+		}
+	}
+
+	// Create and register a new gauge for the next execution of each window
+	nextExecution := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_ssm_maintenance_window_next_execution_timestamp",
+			Help: "Unix timestamp of the next execution of the Systems Manager maintenance window.",
+		},
+		[]string{"WindowId"},
+	)
+	reg.MustRegister(nextExecution)
+	for _, f := range windows {
+		// Disabled windows have no next execution, the time is sent as an ISO 8601 string
+		// which can leave out the seconds
+		if f.NextExecutionTime == nil {
+			continue
+		}
+		next, err := time.Parse(time.RFC3339, *f.NextExecutionTime)
+		if err != nil {
+			next, err = time.Parse("2006-01-02T15:04Z07:00", *f.NextExecutionTime)
+		}
+		if err != nil {
+			return err
+		}
+		nextExecution.WithLabelValues(aws.StringValue(f.WindowId)).Set(float64(next.Unix()))
+	}
+	return nil
+}
+
+// Lists all Step Functions state machine tags and running executions in us-west-2
+func get_stepfunctions_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Step Functions service client
+	svc := sfn.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the state machines
+	stateMachines := make([]*sfn.StateMachineListItem, 0)
+	err := svc.ListStateMachinesPages(&sfn.ListStateMachinesInput{},
+		func(page *sfn.ListStateMachinesOutput, lastPage bool) bool {
+			stateMachines = append(stateMachines, page.StateMachines...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the state machines, gather the tag names and add them to the tags map
+	// Keep the tags for each state machine so we only list them once
+	tags := make(map[string]string)
+	stateMachineTags := make(map[string][]*sfn.Tag)
+	for _, f := range stateMachines {
+		// Create input for ListTagsForResource method
+		input := &sfn.ListTagsForResourceInput{
+			ResourceArn: f.StateMachineArn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		stateMachineTags[*f.StateMachineArn] = resultTags.Tags
+
 		// If the key is not in the map, add it
 		for _, v := range resultTags.Tags {
 			if _, ok := tags[*v.Key]; !ok {
@@ -396,309 +8101,746 @@ func get_efs_tags(region string) {
 		}
 	}
 
-	// Gather all tags for each fileSystem and pupulate fileSystem map
-	fileSystem := make(map[string]map[string]string)
-	// Iterate through all the fileSystem and create one entry with all tags
-	for _, f := range result.FileSystems {
-		// Create input for DescribeTags method
-		fileSystemId := aws.StringValue(f.FileSystemId)
-		input := &efs.DescribeTagsInput{
-			FileSystemId: aws.String(fileSystemId),
+	// Gather all tags for each state machine and pupulate state machine map
+	stateMachine := make(map[string]map[string]string)
+	for _, f := range stateMachines {
+		// Initialize the map for this state machine
+		stateMachine[*f.StateMachineArn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			stateMachine[*f.StateMachineArn][key] = ""
+		}
+
+		// Add metadata as tags
+		stateMachine[*f.StateMachineArn]["Name"] = aws.StringValue(f.Name)
+		stateMachine[*f.StateMachineArn]["Type"] = aws.StringValue(f.Type)
+
+		// Populate the state machine's map with the tag values
+		for _, t := range stateMachineTags[*f.StateMachineArn] {
+			stateMachine[*f.StateMachineArn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per state machine
+	stateMachineGauge := new_collector_result(reg, "aws_stepfunctions_statemachine_tags", "Key:Value metric per Step Functions state machine with all tags.", "StateMachineArn", stateMachine)
+	for key := range stateMachine {
+		stateMachineGauge.Set(key, 1)
+	}
+
+	// Create and register a new gauge for the running executions of each state machine
+	executionCount := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_stepfunctions_execution_count",
+			Help: "Number of RUNNING executions of each Step Functions state machine.",
+		},
+		[]string{"StateMachineArn", "Name"},
+	)
+	reg.MustRegister(executionCount)
+
+	// Page through the running executions of each state machine and count them
+	for _, f := range stateMachines {
+		input := &sfn.ListExecutionsInput{
+			StateMachineArn: f.StateMachineArn,
+			StatusFilter:    aws.String(sfn.ExecutionStatusRunning),
+		}
+		count := 0
+		err := svc.ListExecutionsPages(input,
+			func(page *sfn.ListExecutionsOutput, lastPage bool) bool {
+				count += len(page.Executions)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+		executionCount.WithLabelValues(aws.StringValue(f.StateMachineArn), aws.StringValue(f.Name)).Set(float64(count))
+	}
+	return nil
+}
+
+// Lists all Subnet tags and available IP addresses in us-west-2
+func get_subnet_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the subnets
+	subnets := make([]*ec2.Subnet, 0)
+	err := svc.DescribeSubnetsPages(&ec2.DescribeSubnetsInput{},
+		func(page *ec2.DescribeSubnetsOutput, lastPage bool) bool {
+			subnets = append(subnets, page.Subnets...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the subnets, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range subnets {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each subnet and pupulate subnet map
+	subnet := make(map[string]map[string]string)
+	for _, f := range subnets {
+		// Initialize the map for this subnet
+		subnet[*f.SubnetId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			subnet[*f.SubnetId][key] = ""
+		}
+
+		// Add metadata as tags
+		subnet[*f.SubnetId]["VpcId"] = aws.StringValue(f.VpcId)
+		subnet[*f.SubnetId]["CidrBlock"] = aws.StringValue(f.CidrBlock)
+		subnet[*f.SubnetId]["AvailabilityZone"] = aws.StringValue(f.AvailabilityZone)
+		subnet[*f.SubnetId]["AvailableIpAddressCount"] = strconv.FormatInt(aws.Int64Value(f.AvailableIpAddressCount), 10)
+
+		// Populate the subnet's map with the tag values
+		for _, t := range f.Tags {
+			subnet[*f.SubnetId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per subnet
+	subnetGauge := new_collector_result(reg, "aws_subnet_tags", "Key:Value metric per Subnet with all tags. Value is the number of available IP addresses.", "SubnetId", subnet)
+	for key, value := range subnet {
+		availableIps, _ := strconv.ParseFloat(value["AvailableIpAddressCount"], 64)
+		subnetGauge.Set(key, availableIps)
+	}
+	return nil
+}
+
+// Lists all Timestream database and table tags in us-west-2
+// Timestream serves its API from a discovered endpoint, the SDK calls DescribeEndpoints and caches the result
+func get_timestream_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Timestream service client
+	svc := timestreamwrite.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the databases
+	databases := make([]*timestreamwrite.Database, 0)
+	err := svc.ListDatabasesPages(&timestreamwrite.ListDatabasesInput{},
+		func(page *timestreamwrite.ListDatabasesOutput, lastPage bool) bool {
+			databases = append(databases, page.Databases...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Page through the tables of every database
+	tables := make([]*timestreamwrite.Table, 0)
+	for _, d := range databases {
+		err := svc.ListTablesPages(&timestreamwrite.ListTablesInput{
+			DatabaseName: d.DatabaseName,
+		},
+			func(page *timestreamwrite.ListTablesOutput, lastPage bool) bool {
+				tables = append(tables, page.Tables...)
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate through all the databases, gather the tag names and add them to the tags map
+	// Keep the tags for each database so we only list them once
+	tags := make(map[string]string)
+	databaseTags := make(map[string][]*timestreamwrite.Tag)
+	for _, f := range databases {
+		// Create input for ListTagsForResource method
+		input := &timestreamwrite.ListTagsForResourceInput{
+			ResourceARN: f.Arn,
 		}
 
 		// List out the tags
-		resultTags, err := svc.DescribeTags(input)
+		resultTags, err := svc.ListTagsForResource(input)
 		if err != nil {
-			fmt.Println(err.Error())
-			return
+			return err
+		}
+		databaseTags[*f.Arn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each database and pupulate database map
+	database := make(map[string]map[string]string)
+	for _, f := range databases {
+		// Initialize the map for this database
+		database[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			database[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		database[*f.Arn]["DatabaseName"] = aws.StringValue(f.DatabaseName)
+
+		// Populate the database's map with the tag values
+		for _, t := range databaseTags[*f.Arn] {
+			database[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per database
+	databaseGauge := new_collector_result(reg, "aws_timestream_database_tags", "Key:Value metric per Timestream database with all tags.", "Arn", database)
+	for key := range database {
+		databaseGauge.Set(key, 1)
+	}
+
+	// Iterate through all the tables, gather the tag names and add them to the tableTagNames map
+	// Keep the tags for each table so we only list them once
+	tableTagNames := make(map[string]string)
+	tableTags := make(map[string][]*timestreamwrite.Tag)
+	for _, f := range tables {
+		// Create input for ListTagsForResource method
+		input := &timestreamwrite.ListTagsForResourceInput{
+			ResourceARN: f.Arn,
+		}
+
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return err
+		}
+		tableTags[*f.Arn] = resultTags.Tags
+
+		// If the key is not in the map, add it
+		for _, v := range resultTags.Tags {
+			if _, ok := tableTagNames[*v.Key]; !ok {
+				tableTagNames[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each table and pupulate table map
+	table := make(map[string]map[string]string)
+	for _, f := range tables {
+		// Initialize the map for this table
+		table[*f.Arn] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tableTagNames {
+			table[*f.Arn][key] = ""
+		}
+
+		// Add metadata as tags
+		table[*f.Arn]["DatabaseName"] = aws.StringValue(f.DatabaseName)
+		table[*f.Arn]["TableName"] = aws.StringValue(f.TableName)
+		table[*f.Arn]["TableStatus"] = aws.StringValue(f.TableStatus)
+
+		// Populate the table's map with the tag values
+		for _, t := range tableTags[*f.Arn] {
+			table[*f.Arn][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per table
+	tableGauge := new_collector_result(reg, "aws_timestream_table_tags", "Key:Value metric per Timestream table with all tags. 1 if ACTIVE, 0 otherwise.", "Arn", table)
+	for key, value := range table {
+		if value["TableStatus"] == timestreamwrite.TableStatusActive {
+			tableGauge.Set(key, 1)
+		} else {
+			tableGauge.Set(key, 0)
+		}
+	}
+
+	// Create and register a new gauge for the retention of each table, magnetic store days are converted to hours
+	retention := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_timestream_table_retention_hours",
+			Help: "Retention period in hours of each Timestream table per store.",
+		},
+		[]string{"DatabaseName", "TableName", "Store"},
+	)
+	reg.MustRegister(retention)
+	for _, f := range tables {
+		if f.RetentionProperties == nil {
+			continue
+		}
+		if f.RetentionProperties.MemoryStoreRetentionPeriodInHours != nil {
+			retention.WithLabelValues(aws.StringValue(f.DatabaseName), aws.StringValue(f.TableName), "memory").Set(float64(*f.RetentionProperties.MemoryStoreRetentionPeriodInHours))
+		}
+		if f.RetentionProperties.MagneticStoreRetentionPeriodInDays != nil {
+			retention.WithLabelValues(aws.StringValue(f.DatabaseName), aws.StringValue(f.TableName), "magnetic").Set(float64(*f.RetentionProperties.MagneticStoreRetentionPeriodInDays * 24))
+		}
+	}
+	return nil
+}
+
+// Lists all Transfer Family server tags in us-west-2
+func get_transfer_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Transfer Family service client
+	svc := transfer.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the servers
+	listedServers := make([]*transfer.ListedServer, 0)
+	err := svc.ListServersPages(&transfer.ListServersInput{},
+		func(page *transfer.ListServersOutput, lastPage bool) bool {
+			listedServers = append(listedServers, page.Servers...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// ListServers has no tags, describe each server for its full metadata
+	servers := make([]*transfer.DescribedServer, 0, len(listedServers))
+	for _, f := range listedServers {
+		result, err := svc.DescribeServer(&transfer.DescribeServerInput{
+			ServerId: f.ServerId,
+		})
+		if err != nil {
+			return err
+		}
+		servers = append(servers, result.Server)
+	}
+
+	// Iterate through all the servers, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range servers {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
+
+	// Gather all tags for each server and pupulate server map
+	server := make(map[string]map[string]string)
+	for _, f := range servers {
+		// Initialize the map for this server
+		server[*f.ServerId] = make(map[string]string)
+
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			server[*f.ServerId][key] = ""
+		}
+
+		// Add metadata as tags
+		server[*f.ServerId]["Arn"] = aws.StringValue(f.Arn)
+		server[*f.ServerId]["Domain"] = aws.StringValue(f.Domain)
+		server[*f.ServerId]["EndpointType"] = aws.StringValue(f.EndpointType)
+		server[*f.ServerId]["IdentityProviderType"] = aws.StringValue(f.IdentityProviderType)
+		server[*f.ServerId]["State"] = aws.StringValue(f.State)
+
+		// Populate the server's map with the tag values
+		for _, t := range f.Tags {
+			server[*f.ServerId][*t.Key] = aws.StringValue(t.Value)
+		}
+	}
+
+	// Register a gauge labelled with every tag and create one metric per server
+	serverGauge := new_collector_result(reg, "aws_transfer_server_tags", "Key:Value metric per Transfer Family server with all tags. 1 if ONLINE, 0 otherwise.", "ServerId", server)
+	for key, value := range server {
+		if value["State"] == transfer.StateOnline {
+			serverGauge.Set(key, 1)
+		} else {
+			serverGauge.Set(key, 0)
+		}
+	}
+	return nil
+}
+
+// Lists all Transit Gateway tags in us-west-2
+func get_transit_gateway_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the transit gateways
+	transitGateways := make([]*ec2.TransitGateway, 0)
+	err := svc.DescribeTransitGatewaysPages(&ec2.DescribeTransitGatewaysInput{},
+		func(page *ec2.DescribeTransitGatewaysOutput, lastPage bool) bool {
+			transitGateways = append(transitGateways, page.TransitGateways...)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	// Iterate through all the transit gateways, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range transitGateways {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
 		}
+	}
 
-		// Initialize the map for this FileSystem
-		fileSystem[*f.FileSystemId] = make(map[string]string)
+	// Gather all tags for each transit gateway and pupulate transit gateway map
+	transitGateway := make(map[string]map[string]string)
+	for _, f := range transitGateways {
+		// Initialize the map for this transit gateway
+		transitGateway[*f.TransitGatewayId] = make(map[string]string)
 
 		// Add all keys to the map. It is necessary to have every tag for the metric
 		for key, _ := range tags {
-			fileSystem[*f.FileSystemId][key] = ""
+			transitGateway[*f.TransitGatewayId][key] = ""
 		}
 
-		// Populate the fileSystem's map with the tag values
-		for _, t := range resultTags.Tags {
-			fileSystem[*f.FileSystemId][*t.Key] = *t.Value
+		// Add metadata as tags
+		transitGateway[*f.TransitGatewayId]["TransitGatewayArn"] = aws.StringValue(f.TransitGatewayArn)
+		transitGateway[*f.TransitGatewayId]["State"] = aws.StringValue(f.State)
+		transitGateway[*f.TransitGatewayId]["OwnerId"] = aws.StringValue(f.OwnerId)
+
+		// Populate the transit gateway's map with the tag values
+		for _, t := range f.Tags {
+			transitGateway[*f.TransitGatewayId][*t.Key] = *t.Value
 		}
 	}
 
-	// Create a string slice of keys for sorting
-	keys := make([]string, 0, len(tags)+1)
-	keys = append(keys, "FileSystemId")
-	for k := range tags {
-		keys = append(keys, k)
+	// Register a gauge labelled with every tag and create one metric per transit gateway
+	tgw := new_collector_result(reg, "aws_transit_gateway_tags", "Key:Value metric per Transit Gateway with all tags. 1 if available, 0 otherwise.", "TransitGatewayId", transitGateway)
+	for key, value := range transitGateway {
+		if value["State"] == ec2.TransitGatewayStateAvailable {
+			tgw.Set(key, 1)
+		} else {
+			tgw.Set(key, 0)
+		}
 	}
-	sort.Strings(keys)
+	return nil
+}
 
-	// Make sure all tag names are safe as Prometheus labels
-	sanitizedKeys := make([]string, 0, len(keys))
-	for _, v := range keys {
-		sanitizeKey := sanatize_tag(v)
-		sanitizedKeys = append(sanitizedKeys, sanitizeKey)
+// Report the result of every Trusted Advisor check, which needs a Business or Enterprise support plan
+func get_trusted_advisor_metrics(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create Support service client, the global endpoint lives in us-east-1
+	svc := support.New(sess, &aws.Config{Region: aws.String("us-east-1")})
+
+	// Describe all of the checks
+	result, err := svc.DescribeTrustedAdvisorChecks(&support.DescribeTrustedAdvisorChecksInput{
+		Language: aws.String("en"),
+	})
+	if err != nil {
+		// The SDK has no constant for the error returned without a Business or Enterprise support plan
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "SubscriptionRequiredException" {
+			log.Printf("WARNING: Trusted Advisor needs a Business or Enterprise support plan, skipping")
+			return nil
+		}
+		return err
 	}
 
-	// Create and register a new gauge for prometheus
-	efs := prometheus.NewGaugeVec(
+	// Create and register a new gauge for the status of each check
+	check := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "aws_efs_tags",
-			Help: "Key:Value metric per EFS fileSystem with all tags.",
+			Name: "aws_trusted_advisor_check_status",
+			Help: "Trusted Advisor check result, 1 if ok, 0.5 on a warning and 0 on an error.",
 		},
-		sanitizedKeys,
+		[]string{"CheckId", "Name", "Category", "Status"},
 	)
-	registry.MustRegister(efs)
+	reg.MustRegister(check)
 
-	// Build sort order []string for each filesystem
-	// Create one metric per filesystem with sort ordered labels
-	for key, value := range fileSystem {
-		fileSystemString := make([]string, 0, len(keys))
-		for _, v := range keys {
-			if v == "FileSystemId" {
-				fileSystemString = append(fileSystemString, key)
-			} else {
-				fileSystemString = append(fileSystemString, value[v])
-			}
+	for _, f := range result.Checks {
+		checkResult, err := svc.DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  f.Id,
+			Language: aws.String("en"),
+		})
+		if err != nil {
+			return err
+		}
+		if checkResult.Result == nil {
+			continue
+		}
+
+		// The statuses have no SDK enum, checks which are not available for the account are left out
+		status := aws.StringValue(checkResult.Result.Status)
+		value := 0.0
+		switch status {
+		case "ok":
+			value = 1
+		case "warning":
+			value = 0.5
+		case "error":
+			value = 0
+		default:
+			continue
 		}
-		efs.WithLabelValues(fileSystemString...).Set(1)
+		check.WithLabelValues(aws.StringValue(f.Id), aws.StringValue(f.Name), aws.StringValue(f.Category), status).Set(value)
 	}
+	return nil
 }
 
-// Lists all instances in an elb in us-west-2
-func get_elb_membership(region string) {
-	// Set up for a proxy, if one exists
-	httpclient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(*http.Request) (*url.URL, error) {
-				val, ok := os.LookupEnv("HTTPS_PROXY")
-				if !ok {
-					return nil, nil
-				} else {
-					return url.Parse(val)
-				}
-			},
-		},
+// Lists all Verified Access instance and trust provider tags in us-west-2
+func get_verified_access_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create EC2 service client
+	svc := ec2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the instances
+	instances := make([]*ec2.VerifiedAccessInstance, 0)
+	err := svc.DescribeVerifiedAccessInstancesPages(&ec2.DescribeVerifiedAccessInstancesInput{},
+		func(page *ec2.DescribeVerifiedAccessInstancesOutput, lastPage bool) bool {
+			instances = append(instances, page.VerifiedAccessInstances...)
+			return true
+		})
+	if err != nil {
+		return err
 	}
 
-	// Initialize a session
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	// Iterate through all the instances, gather the tag names and add them to the tags map
+	tags := make(map[string]string)
+	for _, f := range instances {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := tags[*v.Key]; !ok {
+				tags[*v.Key] = ""
+			}
+		}
+	}
 
-	// Create ELB service client
-	svc := elb.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
+	// Gather all tags for each instance and pupulate instance map
+	instance := make(map[string]map[string]string)
+	for _, f := range instances {
+		// Initialize the map for this instance
+		instance[*f.VerifiedAccessInstanceId] = make(map[string]string)
 
-	result, err := svc.DescribeLoadBalancers(nil)
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range tags {
+			instance[*f.VerifiedAccessInstanceId][key] = ""
+		}
 
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+		// Populate the instance's map with the tag values
+		for _, t := range f.Tags {
+			instance[*f.VerifiedAccessInstanceId][*t.Key] = aws.StringValue(t.Value)
+		}
 	}
 
-	// Create and register a new gauge for prometheus
-	elb := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "aws_elb_instances",
-			Help: "Metric per EC2 instances assignd to an ELB.",
-		},
-		[]string{"LoadBalancerName", "DNSName", "InstanceId"},
-	)
-	registry.MustRegister(elb)
+	// Register a gauge labelled with every tag and create one metric per instance
+	instanceGauge := new_collector_result(reg, "aws_verified_access_instance_tags", "Key:Value metric per Verified Access instance with all tags.", "VerifiedAccessInstanceId", instance)
+	for key := range instance {
+		instanceGauge.Set(key, 1)
+	}
 
-	// Iterate through all groups, gather instances adding a metric for each
-	for _, f := range result.LoadBalancerDescriptions {
-		for _, v := range f.Instances {
-			elb.WithLabelValues(aws.StringValue(f.LoadBalancerName), aws.StringValue(f.DNSName), *v.InstanceId).Set(1)
-		}
+	// Page through all of the trust providers
+	trustProviders := make([]*ec2.VerifiedAccessTrustProvider, 0)
+	err = svc.DescribeVerifiedAccessTrustProvidersPages(&ec2.DescribeVerifiedAccessTrustProvidersInput{},
+		func(page *ec2.DescribeVerifiedAccessTrustProvidersOutput, lastPage bool) bool {
+			trustProviders = append(trustProviders, page.VerifiedAccessTrustProviders...)
+			return true
+		})
+	if err != nil {
+		return err
 	}
-}
 
-// Lists all Lambda functions in us-west-2
-func get_lambda_tags(region string) {
-	// Set up for a proxy, if one exists
-	httpclient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(*http.Request) (*url.URL, error) {
-				val, ok := os.LookupEnv("HTTPS_PROXY")
-				if !ok {
-					return nil, nil
-				} else {
-					return url.Parse(val)
-				}
-			},
-		},
+	// Iterate through all the trust providers, gather the tag names and add them to the trustProviderTags map
+	trustProviderTags := make(map[string]string)
+	for _, f := range trustProviders {
+		for _, v := range f.Tags {
+			// If the key is not in the map, add it
+			if _, ok := trustProviderTags[*v.Key]; !ok {
+				trustProviderTags[*v.Key] = ""
+			}
+		}
 	}
 
-	// Initialize a session
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	// Gather all tags for each trust provider and pupulate trust provider map
+	trustProvider := make(map[string]map[string]string)
+	for _, f := range trustProviders {
+		// Initialize the map for this trust provider
+		trustProvider[*f.VerifiedAccessTrustProviderId] = make(map[string]string)
 
-	// Create Lambda service client
-	svc := lambda.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
+		// Add all keys to the map. It is necessary to have every tag for the metric
+		for key, _ := range trustProviderTags {
+			trustProvider[*f.VerifiedAccessTrustProviderId][key] = ""
+		}
 
-	result, err := svc.ListFunctions(nil)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+		// Add metadata as tags
+		trustProvider[*f.VerifiedAccessTrustProviderId]["TrustProviderType"] = aws.StringValue(f.TrustProviderType)
+		trustProvider[*f.VerifiedAccessTrustProviderId]["PolicyReferenceName"] = aws.StringValue(f.PolicyReferenceName)
+
+		// Populate the trust provider's map with the tag values
+		for _, t := range f.Tags {
+			trustProvider[*f.VerifiedAccessTrustProviderId][*t.Key] = aws.StringValue(t.Value)
+		}
 	}
 
-	// Iterate through all the functions, gather the tag names and add them to the tags map
+	// Register a gauge labelled with every tag and create one metric per trust provider
+	trustProviderGauge := new_collector_result(reg, "aws_verified_access_trust_provider_tags", "Key:Value metric per Verified Access trust provider with all tags.", "VerifiedAccessTrustProviderId", trustProvider)
+	for key := range trustProvider {
+		trustProviderGauge.Set(key, 1)
+	}
+	return nil
+}
+
+// Lists all WAFv2 WebACL tags and rule counts in us-west-2
+// CLOUDFRONT scoped WebACLs are always looked up in us-east-1, a failing scope is skipped so the other is kept
+func get_waf_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Each scope with the region its API calls must be made against, in a fixed order
+	scopes := []struct {
+		scope  string
+		region string
+	}{
+		{wafv2.ScopeRegional, region},
+		{wafv2.ScopeCloudfront, "us-east-1"},
+	}
+
+	// Iterate through both scopes, gather the WebACLs and their tags
+	// Keep the tags and rule count for each WebACL so we only look them up once
 	tags := make(map[string]string)
-	for _, f := range result.Functions {
-		// Create input for ListTags method
-		arn := aws.StringValue(f.FunctionArn)
-		input := &lambda.ListTagsInput{
-			Resource: aws.String(arn),
-		}
-		// List out the tags
-		resultTags, err := svc.ListTags(input)
+	webACLTags := make(map[string][]*wafv2.Tag)
+	webACLs := make(map[string]wafWebACL)
+	for _, s := range scopes {
+		scopeACLs, err := get_waf_scope(sess, s.scope, s.region, webACLTags)
 		if err != nil {
-			fmt.Println(err.Error())
-			return
+			log.Printf("WARNING: Could not list the %s WAFv2 WebACLs in %s, skipping: %v", s.scope, s.region, err)
+			continue
 		}
+		for arn, f := range scopeACLs {
+			webACLs[arn] = f
 
-		// If the key is not in the map, add it
-		for k, _ := range resultTags.Tags {
-			if _, ok := tags[k]; !ok {
-				tags[k] = ""
+			// If the key is not in the map, add it
+			for _, v := range webACLTags[arn] {
+				if _, ok := tags[*v.Key]; !ok {
+					tags[*v.Key] = ""
+				}
 			}
 		}
 	}
 
-	// Gather all tags for each function and pupulate function map
-	function := make(map[string]map[string]string)
-	// Iterate through all the functions and create one entry with all tags
-	for _, f := range result.Functions {
-		// Create input for ListTags method
-		arn := aws.StringValue(f.FunctionArn)
-		input := &lambda.ListTagsInput{
-			Resource: aws.String(arn),
-		}
-		// List out the tags
-		resultTags, err := svc.ListTags(input)
-		if err != nil {
-			fmt.Println(err.Error())
-			return
-		}
-
-		// Initialize the map for this FileSystem
-		function[*f.FunctionArn] = make(map[string]string)
+	// Gather all tags for each WebACL and pupulate webACL map
+	webACL := make(map[string]map[string]string)
+	for arn, f := range webACLs {
+		// Initialize the map for this WebACL
+		webACL[arn] = make(map[string]string)
 
 		// Add all keys to the map. It is necessary to have every tag for the metric
 		for key, _ := range tags {
-			function[*f.FunctionArn][key] = ""
+			webACL[arn][key] = ""
 		}
 
 		// Add metadata as tags
-		function[*f.FunctionArn]["FunctionName"] = aws.StringValue(f.FunctionName)
-		function[*f.FunctionArn]["Description"] = aws.StringValue(f.Description)
+		webACL[arn]["Name"] = f.name
+		webACL[arn]["Scope"] = f.scope
 
-		// Populate the function's map with the tag values
-		for k, v := range resultTags.Tags {
-			function[*f.FunctionArn][k] = *v
+		// Populate the WebACL's map with the tag values
+		for _, t := range webACLTags[arn] {
+			webACL[arn][*t.Key] = aws.StringValue(t.Value)
 		}
 	}
 
-	// Create a string slice of keys for sorting
-	keys := make([]string, 0, len(tags)+1)
-	keys = append(keys, "FunctionArn")
-	keys = append(keys, "FunctionName")
-	keys = append(keys, "Description")
-	for k := range tags {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	// Make sure all tag names are safe as Prometheus labels
-	sanitizedKeys := make([]string, 0, len(keys))
-	for _, v := range keys {
-		sanitizeKey := sanatize_tag(v)
-		sanitizedKeys = append(sanitizedKeys, sanitizeKey)
-	}
+	// Register a gauge labelled with every tag
+	wafTags := new_collector_result(reg, "aws_wafv2_webacl_tags", "Key:Value metric per WAFv2 WebACL with all tags.", "ARN", webACL)
 
-	// Create and register a new gauge for prometheus
-	lambda := prometheus.NewGaugeVec(
+	// Create and register a new gauge for the number of rules in each WebACL
+	wafRules := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "aws_lambda_tags",
-			Help: "Key:Value metric per Lambda function with all tags.",
+			Name: "aws_wafv2_webacl_rule_count",
+			Help: "Number of rules in each WAFv2 WebACL.",
 		},
-		sanitizedKeys,
+		[]string{"Name", "ARN", "Scope"},
 	)
-	registry.MustRegister(lambda)
+	reg.MustRegister(wafRules)
 
-	// Build sort order []string for each filesystem
-	// Create one metric per filesystem with sort ordered labels
-	for key, value := range function {
-		functionString := make([]string, 0, len(keys))
-		for _, v := range keys {
-			if v == "FunctionArn" {
-				functionString = append(functionString, key)
-			} else {
-				functionString = append(functionString, value[v])
-			}
-		}
-		lambda.WithLabelValues(functionString...).Set(1)
+	// Create one metric per WebACL, the rule count is labelled from the WebACL itself so a Name tag can't replace it
+	for arn, f := range webACLs {
+		wafTags.Set(arn, 1)
+		wafRules.WithLabelValues(f.name, arn, f.scope).Set(float64(f.rules))
 	}
+	return nil
 }
 
-// Lists all RDS tags in us-west-2
-func get_rds_tags(region string) {
-	// Set up for a proxy, if one exists
-	httpclient := &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(*http.Request) (*url.URL, error) {
-				val, ok := os.LookupEnv("HTTPS_PROXY")
-				if !ok {
-					return nil, nil
-				} else {
-					return url.Parse(val)
-				}
-			},
-		},
+// A WAFv2 WebACL along with the scope it was found in
+type wafWebACL struct {
+	name  string
+	scope string
+	rules int
+}
+
+// Lists the WebACLs of one WAFv2 scope keyed by ARN, their tags are stored in webACLTags
+func get_waf_scope(sess *session.Session, scope string, region string, webACLTags map[string][]*wafv2.Tag) (map[string]wafWebACL, error) {
+	// Create WAFv2 service client
+	svc := wafv2.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the WebACLs for this scope
+	summaries := make([]*wafv2.WebACLSummary, 0)
+	input := &wafv2.ListWebACLsInput{
+		Scope: aws.String(scope),
+	}
+	for {
+		result, err := svc.ListWebACLs(input)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, result.WebACLs...)
+		if aws.StringValue(result.NextMarker) == "" {
+			break
+		}
+		input.NextMarker = result.NextMarker
 	}
 
-	// Initialize a session
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	webACLs := make(map[string]wafWebACL)
+	for _, f := range summaries {
+		// List out the tags
+		resultTags, err := svc.ListTagsForResource(&wafv2.ListTagsForResourceInput{
+			ResourceARN: f.ARN,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resultTags.TagInfoForResource != nil {
+			webACLTags[*f.ARN] = resultTags.TagInfoForResource.TagList
+		}
 
-	// Create RDS service client
-	svc := rds.New(sess, &aws.Config{
-		Region:     aws.String(region),
-		HTTPClient: httpclient,
-	})
+		// Look up the WebACL to count its rules
+		var resultACL *wafv2.GetWebACLOutput
+		resultACL, err = svc.GetWebACL(&wafv2.GetWebACLInput{
+			Id:    f.Id,
+			Name:  f.Name,
+			Scope: aws.String(scope),
+		})
+		if err != nil {
+			return nil, err
+		}
+		webACLs[*f.ARN] = wafWebACL{aws.StringValue(f.Name), scope, len(resultACL.WebACL.Rules)}
+	}
+	return webACLs, nil
+}
 
-	result, err := svc.DescribeDBInstances(nil)
+// Lists all WorkSpaces tags and states in us-west-2
+func get_workspaces_tags(sess *session.Session, region string, reg prometheus.Registerer) error {
+	// Create WorkSpaces service client
+	svc := workspaces.New(sess, &aws.Config{Region: aws.String(region)})
+
+	// Page through all of the workspaces
+	workspacesList := make([]*workspaces.Workspace, 0)
+	err := svc.DescribeWorkspacesPages(&workspaces.DescribeWorkspacesInput{},
+		func(page *workspaces.DescribeWorkspacesOutput, lastPage bool) bool {
+			workspacesList = append(workspacesList, page.Workspaces...)
+			return true
+		})
 	if err != nil {
-		fmt.Println(err.Error())
-		return
+		return err
 	}
 
-	// Iterate through all the dBInstances, gather the tag names and add them to the tags map
+	// Iterate through all the workspaces, gather the tag names and add them to the tags map
+	// Keep the tags for each workspace so we only list them once
 	tags := make(map[string]string)
-	for _, f := range result.DBInstances {
-		// Create input for ListTagsForResource method
-		resourceName := aws.StringValue(f.DBInstanceArn)
-		input := &rds.ListTagsForResourceInput{
-			ResourceName: aws.String(resourceName),
+	workspaceTags := make(map[string][]*workspaces.Tag)
+	for _, f := range workspacesList {
+		// Create input for DescribeTags method
+		input := &workspaces.DescribeTagsInput{
+			ResourceId: f.WorkspaceId,
 		}
 
 		// List out the tags
-		resultTags, err := svc.ListTagsForResource(input)
+		resultTags, err := svc.DescribeTags(input)
 		if err != nil {
-			fmt.Println(err.Error())
-			return
+			return err
 		}
+		workspaceTags[*f.WorkspaceId] = resultTags.TagList
 
 		// If the key is not in the map, add it
 		for _, v := range resultTags.TagList {
@@ -708,78 +8850,51 @@ func get_rds_tags(region string) {
 		}
 	}
 
-	// Gather all tags for each dbInstance and pupulate dbInstance map
-	dbInstance := make(map[string]map[string]string)
-	for _, f := range result.DBInstances {
-		// Create input for ListTagsForResource method
-		resourceName := aws.StringValue(f.DBInstanceArn)
-		input := &rds.ListTagsForResourceInput{
-			ResourceName: aws.String(resourceName),
-		}
-
-		// List out the tags
-		resultTags, err := svc.ListTagsForResource(input)
-		if err != nil {
-			fmt.Println(err.Error())
-			return
-		}
-
-		// Initialize the map for this dbInstance
-		dbInstance[*f.DBInstanceArn] = make(map[string]string)
+	// Gather all tags for each workspace and pupulate workspace map
+	workspace := make(map[string]map[string]string)
+	for _, f := range workspacesList {
+		// Initialize the map for this workspace
+		workspace[*f.WorkspaceId] = make(map[string]string)
 
 		// Add all keys to the map. It is necessary to have every tag for the metric
 		for key, _ := range tags {
-			dbInstance[*f.DBInstanceArn][key] = ""
+			workspace[*f.WorkspaceId][key] = ""
 		}
 
 		// Add metadata as tags
-		dbInstance[*f.DBInstanceArn]["DBName"] = aws.StringValue(f.DBName)
-		dbInstance[*f.DBInstanceArn]["DBInstanceIdentifier"] = aws.StringValue(f.DBInstanceIdentifier)
+		workspace[*f.WorkspaceId]["DirectoryId"] = aws.StringValue(f.DirectoryId)
+		workspace[*f.WorkspaceId]["UserName"] = aws.StringValue(f.UserName)
+		workspace[*f.WorkspaceId]["BundleId"] = aws.StringValue(f.BundleId)
+		workspace[*f.WorkspaceId]["State"] = aws.StringValue(f.State)
 
-		// Populate the dbInstance's map with the tag values
-		for _, t := range resultTags.TagList {
-			dbInstance[*f.DBInstanceArn][*t.Key] = *t.Value
+		// Populate the workspace's map with the tag values
+		for _, t := range workspaceTags[*f.WorkspaceId] {
+			workspace[*f.WorkspaceId][*t.Key] = aws.StringValue(t.Value)
 		}
 	}
 
-	// Create a string slice of keys for sorting
-	keys := make([]string, 0, len(tags)+1)
-	keys = append(keys, "DBInstanceArn")
-	keys = append(keys, "DBName")
-	keys = append(keys, "DBInstanceIdentifier")
-	for k := range tags {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	// Make sure all tag names are safe as Prometheus labels
-	sanitizedKeys := make([]string, 0, len(keys))
-	for _, v := range keys {
-		sanitizeKey := sanatize_tag(v)
-		sanitizedKeys = append(sanitizedKeys, sanitizeKey)
+	// Register a gauge labelled with every tag and create one metric per workspace
+	workspaceGauge := new_collector_result(reg, "aws_workspaces_tags", "Key:Value metric per WorkSpace with all tags. 1 if AVAILABLE, 0 otherwise.", "WorkspaceId", workspace)
+	for key, value := range workspace {
+		if value["State"] == workspaces.WorkspaceStateAvailable {
+			workspaceGauge.Set(key, 1)
+		} else {
+			workspaceGauge.Set(key, 0)
+		}
 	}
 
-	// Create and register a new gauge for prometheus
-	rds := prometheus.NewGaugeVec(
+	// Create and register an info gauge for the state of each workspace
+	workspaceState := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "aws_rds_tags",
-			Help: "Key:Value metric per RDS instance with all tags.",
+			Name: "aws_workspaces_state",
+			Help: "Info metric per WorkSpace with its current state.",
 		},
-		sanitizedKeys,
+		[]string{"WorkspaceId", "State"},
 	)
-	registry.MustRegister(rds)
+	reg.MustRegister(workspaceState)
 
-	// Build sort order []string for each dbInstance
-	// Create one metric per dbInstance with sort ordered labels
-	for key, value := range dbInstance {
-		dbInstanceString := make([]string, 0, len(keys))
-		for _, v := range keys {
-			if v == "DBInstanceArn" {
-				dbInstanceString = append(dbInstanceString, key)
-			} else {
-				dbInstanceString = append(dbInstanceString, value[v])
-			}
-		}
-		rds.WithLabelValues(dbInstanceString...).Set(1)
+	for _, f := range workspacesList {
+		workspaceState.WithLabelValues(aws.StringValue(f.WorkspaceId), aws.StringValue(f.State)).Set(1)
 	}
+	return nil
 }
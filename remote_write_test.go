@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// Flatten the labels of a time series into name=value pairs
+func series_labels(ts prompb.TimeSeries) map[string]string {
+	labels := make(map[string]string)
+	for _, l := range ts.Labels {
+		labels[l.Name] = l.Value
+	}
+	return labels
+}
+
+func TestToTimeseries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "aws_ec2_tags", Help: "test"},
+		[]string{"InstanceId"},
+	)
+	histogram := prometheus.NewHistogram(
+		prometheus.HistogramOpts{Name: "aws_api_call_duration_seconds", Help: "test", Buckets: []float64{1}},
+	)
+	reg.MustRegister(gauge, histogram)
+	gauge.WithLabelValues("i-1234").Set(1)
+	histogram.Observe(0.5)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := to_timeseries(mfs, 1000)
+
+	// One gauge series, two buckets, the sum and the count
+	if len(series) != 5 {
+		t.Fatalf("expected 5 series, got %d", len(series))
+	}
+	found := make(map[string]bool)
+	for _, ts := range series {
+		labels := series_labels(ts)
+		for i := 1; i < len(ts.Labels); i++ {
+			if ts.Labels[i-1].Name >= ts.Labels[i].Name {
+				t.Errorf("expected labels sorted by name, got %v", ts.Labels)
+			}
+		}
+		if len(ts.Samples) != 1 || ts.Samples[0].Timestamp != 1000 {
+			t.Errorf("expected one sample at timestamp 1000, got %v", ts.Samples)
+		}
+		found[labels["__name__"]+labels["le"]] = true
+		if labels["__name__"] == "aws_ec2_tags" && labels["InstanceId"] != "i-1234" {
+			t.Errorf("expected InstanceId label i-1234, got %v", labels)
+		}
+	}
+	for _, name := range []string{"aws_ec2_tags", "aws_api_call_duration_seconds_bucket1", "aws_api_call_duration_seconds_bucket+Inf", "aws_api_call_duration_seconds_sum", "aws_api_call_duration_seconds_count"} {
+		if !found[name] {
+			t.Errorf("expected a series for %s", name)
+		}
+	}
+}
+
+func TestRemoteWrite(t *testing.T) {
+	var received prompb.WriteRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "snappy" {
+			t.Errorf("expected snappy encoding, got %q", r.Header.Get("Content-Encoding"))
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		compressed, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := received.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "aws_metrics_last_collected_timestamp_seconds", Help: "test"})
+	reg.MustRegister(gauge)
+	gauge.Set(42)
+
+	if err := remote_write(reg, server.URL, "secret", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(received.Timeseries) != 1 || received.Timeseries[0].Samples[0].Value != 42 {
+		t.Errorf("expected one series with value 42, got %v", received.Timeseries)
+	}
+}
+
+func TestRemoteWrite_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if err := remote_write(prometheus.NewRegistry(), server.URL, "", ""); err == nil {
+		t.Error("expected an error for a 400 response")
+	}
+}
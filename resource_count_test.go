@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCountingRegisterer(t *testing.T) {
+	reg := &countingRegisterer{Registerer: prometheus.NewRegistry()}
+
+	// Every tag metric adds its resources, a collector registering its own gauges adds them itself
+	register_instance(reg, "i-1111", map[string]string{"Name": "web"})
+	new_collector_result(reg, "aws_ebs_tags", "test", "VolumeId", map[string]map[string]string{
+		"vol-1111": {"Name": "web"},
+		"vol-2222": {"Name": "db"},
+	})
+	count_resources(reg, 4)
+
+	if reg.results != 3 || reg.resources != 7 {
+		t.Errorf("expected 7 resources from 3 results, got %d from %d", reg.resources, reg.results)
+	}
+}
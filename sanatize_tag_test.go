@@ -0,0 +1,190 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSanatizeTag(t *testing.T) {
+	// Every output must be a valid Prometheus label name
+	labelRegex := regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+	tests := []struct {
+		name     string
+		tag      string
+		expected string
+	}{
+		// Valid tags are returned untouched
+		{name: "valid tag", tag: "Name", expected: "Name"},
+		// Every invalid segment separator becomes an underscore
+		{name: "tag with colons", tag: "aws:autoscaling:groupName", expected: "aws_autoscaling_groupName"},
+		// Tags starting with a digit are padded with an underscore
+		{name: "tag starting with digit", tag: "1tag", expected: "_1tag"},
+		// Each special character is replaced by an underscore
+		{name: "all special characters", tag: "!@#", expected: "___"},
+		// An empty tag is padded into a lone underscore
+		{name: "empty string", tag: "", expected: "_"},
+		// Multi-byte characters are not valid and collapse into an underscore
+		{name: "unicode characters", tag: "tëst", expected: "t_st"},
+		{name: "only unicode characters", tag: "日本", expected: "__"},
+		{name: "single valid character", tag: "a", expected: "a"},
+		{name: "single invalid character", tag: "-", expected: "_"},
+		{name: "single digit", tag: "0", expected: "_0"},
+		// The reserved '__' prefix matches the label regex and is not altered
+		{name: "reserved prefix", tag: "__", expected: "__"},
+		{name: "tag with spaces", tag: "Cost Center", expected: "Cost_Center"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := sanatize_tag(tc.tag)
+			if result != tc.expected {
+				t.Errorf("sanatize_tag(%q) = %q, expected %q", tc.tag, result, tc.expected)
+			}
+			if !labelRegex.MatchString(result) {
+				t.Errorf("sanatize_tag(%q) = %q, which is not a valid label name", tc.tag, result)
+			}
+		})
+	}
+}
+
+func TestSanatizeTagCollision(t *testing.T) {
+	// Different tags can sanitize to the same label name
+	first := sanatize_tag("team:name")
+	second := sanatize_tag("team-name")
+	if first != "team_name" || second != "team_name" {
+		t.Errorf("expected both tags to sanitize to %q, got %q and %q", "team_name", first, second)
+	}
+}
+
+func TestDedupeLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		expected []string
+	}{
+		{name: "unique labels", labels: []string{"Name", "team_name"}, expected: []string{"Name", "team_name"}},
+		{name: "two collisions", labels: []string{"team_name", "team_name"}, expected: []string{"team_name", "team_name_2"}},
+		{name: "three collisions", labels: []string{"a", "a", "a"}, expected: []string{"a", "a_2", "a_3"}},
+		// A suffixed name must not clash with a label which already has that name
+		{name: "suffix already taken", labels: []string{"a", "a_2", "a"}, expected: []string{"a", "a_2", "a_3"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := dedupe_labels(tc.labels)
+			if strings.Join(result, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("dedupe_labels(%v) = %v, expected %v", tc.labels, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLabelNames(t *testing.T) {
+	defer func() { tagRenames = make(tagRenameFlag) }()
+	keys := []string{"Name", "aws:cloudformation:stack-name", "aws_cloudformation_stack_name", "team:name"}
+
+	// Without renames every key is sanitized and deduplicated in order
+	result := label_names(keys)
+	if strings.Join(result, ",") != "Name,aws_cloudformation_stack_name,aws_cloudformation_stack_name_2,team_name" {
+		t.Errorf("label_names(%v) = %v", keys, result)
+	}
+
+	// A renamed tag keeps its name and the tag colliding with it is suffixed instead
+	tagRenames = tagRenameFlag{"aws:cloudformation:stack-name": "cfn_stack", "team:name": "Name"}
+	result = label_names(keys)
+	if strings.Join(result, ",") != "Name_2,cfn_stack,aws_cloudformation_stack_name,Name" {
+		t.Errorf("label_names(%v) with renames = %v", keys, result)
+	}
+
+	// The account_id label is reserved, a tag sanitizing to it is suffixed
+	keys = []string{"account:id", "account_id"}
+	result = label_names(keys)
+	if strings.Join(result, ",") != "account_id_2,account_id_3" {
+		t.Errorf("label_names(%v) = %v", keys, result)
+	}
+}
+
+func TestTagRenameFlag(t *testing.T) {
+	renames := make(tagRenameFlag)
+
+	// Tag keys may hold an equals sign, the label name follows the last one
+	for _, value := range []string{"aws:cloudformation:stack-name=cfn_stack", "a=b=c"} {
+		if err := renames.Set(value); err != nil {
+			t.Fatalf("Set(%q) returned %v", value, err)
+		}
+	}
+	if renames["aws:cloudformation:stack-name"] != "cfn_stack" || renames["a=b"] != "c" {
+		t.Errorf("unexpected renames %v", renames)
+	}
+
+	// Invalid label names, the reserved account_id label and two keys renamed to the same label are rejected
+	for _, value := range []string{"stack", "=cfn_stack", "stack=cfn-stack", "stack=1stack", "other=cfn_stack", "account=account_id"} {
+		if err := renames.Set(value); err == nil {
+			t.Errorf("expected Set(%q) to fail", value)
+		}
+	}
+}
+
+func TestTruncateValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		max      int
+		expected string
+	}{
+		{name: "unlimited", value: "a long value", max: 0, expected: "a long value"},
+		{name: "shorter than max", value: "short", max: 10, expected: "short"},
+		{name: "exactly max", value: "exact", max: 5, expected: "exact"},
+		{name: "longer than max", value: "{\"json\":\"blob\"}", max: 4, expected: "{\"js..."},
+		// Multi-byte characters count as one and are never cut in half
+		{name: "unicode characters", value: "日本語のタグ", max: 3, expected: "日本語..."},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := truncateValue(tc.value, tc.max); result != tc.expected {
+				t.Errorf("truncateValue(%q, %d) = %q, expected %q", tc.value, tc.max, result, tc.expected)
+			}
+		})
+	}
+}
+
+// Realistic AWS tag keys, the first half is already valid and the second half needs sanitizing
+var benchmarkTags = []string{
+	"Name", "Environment", "Owner", "Team", "Project",
+	"CostCenter", "Application", "Service", "Stack", "Role",
+	"Component", "Version", "Department", "BusinessUnit", "Customer",
+	"Platform", "Region", "Tier", "Backup", "Schedule",
+	"ManagedBy", "Terraform", "Compliance", "DataClassification", "nubis_project",
+	"aws:autoscaling:groupName", "aws:cloudformation:stack-name", "aws:cloudformation:stack-id", "aws:cloudformation:logical-id", "aws:ec2spot:fleet-request-id",
+	"aws:ec2launchtemplate:id", "aws:ec2launchtemplate:version", "aws:eks:cluster-name", "aws:elasticmapreduce:job-flow-id", "aws:servicecatalog:productArn",
+	"kubernetes.io/cluster/production", "kubernetes.io/role/elb", "k8s.io/cluster-autoscaler/enabled", "eks:cluster-name", "eks:nodegroup-name",
+	"Cost Center", "cost-center", "created-by", "app.kubernetes.io/name", "team:name",
+	"1password-vault", "2fa-required", "owner@example.com", "backup-policy", "Patch Group",
+}
+
+func BenchmarkSanatizeTag(b *testing.B) {
+	half := len(benchmarkTags) / 2
+	benchmarks := []struct {
+		name string
+		tags []string
+	}{
+		// Valid tags return after the first match
+		{name: "fast path", tags: benchmarkTags[:half]},
+		// Invalid tags are split, restitched and padded
+		{name: "slow path", tags: benchmarkTags[half:]},
+		{name: "all tags", tags: benchmarkTags},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				for _, tag := range bm.tags {
+					sanatize_tag(tag)
+				}
+			}
+		})
+	}
+}
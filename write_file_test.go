@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// write_file calls log.Fatal on errors, so failure cases are run in a subprocess
+// which writes to the file named by this environment variable
+const writeFileSubprocessEnv = "WRITE_FILE_SUBPROCESS_OUT_FILE"
+
+// Re-run the named test in a subprocess and return its exit error, if any
+func run_write_file_subprocess(t *testing.T, test string, outFile string) error {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^"+test+"$")
+	cmd.Env = append(os.Environ(), writeFileSubprocessEnv+"="+outFile)
+	return cmd.Run()
+}
+
+func TestWriteFile_Success(t *testing.T) {
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "custom_metrics.prom")
+	contents := "aws_asg_instances{InstanceId=\"i-1234\"} 1\n"
+
+	write_file(outFile, contents, "text")
+
+	// The final file has the expected content
+	data, err := ioutil.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != contents {
+		t.Errorf("expected file contents %q, got %q", contents, string(data))
+	}
+
+	// The final file keeps the temp file permissions
+	info, err := os.Stat(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600, got %#o", info.Mode().Perm())
+	}
+
+	// The temp file was renamed away, only the final file remains
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "custom_metrics.prom" {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("expected only custom_metrics.prom in output directory, got %v", names)
+	}
+}
+
+func TestWriteFile_ProtobufExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	// A .prom out-file is switched to the protobuf extension
+	write_file(filepath.Join(dir, "custom_metrics.prom"), "test\n", "protobuf")
+	if _, err := os.Stat(filepath.Join(dir, "custom_metrics.pb")); err != nil {
+		t.Errorf("expected custom_metrics.pb to be written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "custom_metrics.prom")); !os.IsNotExist(err) {
+		t.Errorf("expected custom_metrics.prom not to be written")
+	}
+
+	// Any other extension is kept as given
+	write_file(filepath.Join(dir, "custom_metrics.bin"), "test\n", "protobuf")
+	if _, err := os.Stat(filepath.Join(dir, "custom_metrics.bin")); err != nil {
+		t.Errorf("expected custom_metrics.bin to be written: %v", err)
+	}
+}
+
+func TestWriteFile_DestinationNotWritable(t *testing.T) {
+	if outFile := os.Getenv(writeFileSubprocessEnv); outFile != "" {
+		write_file(outFile, "test\n", "text")
+		return
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for root")
+	}
+
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0755)
+	outFile := filepath.Join(dir, "custom_metrics.prom")
+
+	err := run_write_file_subprocess(t, "TestWriteFile_DestinationNotWritable", outFile)
+	if e, ok := err.(*exec.ExitError); !ok || e.Success() {
+		t.Fatalf("expected write_file to exit with an error, got %v", err)
+	}
+	if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to be written", outFile)
+	}
+}
+
+func TestWriteFile_TmpFileCollision(t *testing.T) {
+	if outFile := os.Getenv(writeFileSubprocessEnv); outFile != "" {
+		write_file(outFile, "test\n", "text")
+		return
+	}
+
+	// Occupy every temp file name write_file can pick so the exclusive create fails
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "custom_metrics.prom")
+	for i := 0; i < 10000; i++ {
+		tmpName := filepath.Join(dir, fmt.Sprintf("custom_metrics.prom.tmp%d", i))
+		if err := ioutil.WriteFile(tmpName, []byte("existing\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := run_write_file_subprocess(t, "TestWriteFile_TmpFileCollision", outFile)
+	if e, ok := err.(*exec.ExitError); !ok || e.Success() {
+		t.Fatalf("expected write_file to exit with an error, got %v", err)
+	}
+	if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to be written", outFile)
+	}
+
+	// The pre-existing temp file must not be removed or overwritten
+	data, err := ioutil.ReadFile(filepath.Join(dir, "custom_metrics.prom.tmp0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "existing\n" {
+		t.Errorf("expected existing temp file to be left untouched, got %q", string(data))
+	}
+}